@@ -1,15 +1,91 @@
 package main
 
 import (
+	"context"
 	"ignite-api/internal/api"
+	"ignite-api/internal/auth"
+	"ignite-api/internal/clusters"
+	"ignite-api/internal/jobs"
+	"ignite-api/internal/lifecycle"
 	"ignite-api/internal/logger"
+	"ignite-api/internal/provision"
+	"ignite-api/internal/provisioner"
+	"ignite-api/internal/store"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/google/uuid"
 )
 
 func main() {
+	logger.Init(logger.Config{
+		Level:      os.Getenv("LOG_LEVEL"),
+		Format:     os.Getenv("LOG_FORMAT"),
+		OutputPath: os.Getenv("LOG_FILE"),
+	})
+
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		dbPath = "vms.db"
+	}
+	repo, err := store.Open(dbPath)
+	if err != nil {
+		logger.Fatal("Failed to open VM store: %v", err)
+	}
+
+	prov, err := provisioner.New(os.Getenv("VM_BACKEND"))
+	if err != nil {
+		logger.Fatal("Failed to initialize VM backend: %v", err)
+	}
+
+	engine := provision.New(
+		provision.WithStore(repo),
+		provision.WithRunner(prov),
+		provision.WithValidator(provision.DefaultValidator(repo)),
+		provision.WithNodeID(uuid.NewString),
+	)
+
+	maxConcurrency, _ := strconv.Atoi(os.Getenv("MAX_CONCURRENT_PROVISIONS"))
+	manager := jobs.NewManager(engine, repo, maxConcurrency)
+	if err := manager.Resume(context.Background()); err != nil {
+		logger.Fatal("Failed to resume in-flight jobs: %v", err)
+	}
+	clusterManager := clusters.NewManager(repo, repo, prov)
+
+	bearer := auth.NewBearerAuthenticator(repo)
+	if err := auth.BootstrapAdminToken(context.Background(), repo, os.Getenv("BOOTSTRAP_ADMIN_TOKEN")); err != nil {
+		logger.Fatal("Failed to bootstrap admin token: %v", err)
+	}
+
+	auths := []auth.Authenticator{bearer}
+	if jwksURL := os.Getenv("JWKS_URL"); jwksURL != "" {
+		keyfunc, err := auth.FetchJWKSKeyfunc(jwksURL)
+		if err != nil {
+			logger.Fatal("Failed to initialize JWT authenticator: %v", err)
+		}
+		roleClaim := os.Getenv("JWT_ROLE_CLAIM")
+		if roleClaim == "" {
+			roleClaim = "role"
+		}
+		auths = append(auths, auth.NewJWTAuthenticator(keyfunc, roleClaim))
+	}
+
+	var mtlsAuth *auth.MTLSAuthenticator
+	if roleMap := os.Getenv("MTLS_ROLE_MAP"); roleMap != "" {
+		mtlsAuth = auth.NewMTLSAuthenticator(parseMTLSRoleMap(roleMap))
+	}
+
+	// require builds the Require middleware for a route with whichever
+	// authenticators are enabled via JWKS_URL/MTLS_ROLE_MAP, so every route
+	// below stays in sync without repeating the enabled set at each call site.
+	require := func(role auth.Role) fiber.Handler {
+		return auth.Require(role, mtlsAuth, auths...)
+	}
+
 	app := fiber.New()
 
 	// Add request ID middleware
@@ -20,20 +96,78 @@ func main() {
 		start := time.Now()
 		err := c.Next()
 		duration := time.Since(start)
-		logger.RequestLog(c.Method(), c.Path(), c.IP(), duration)
+		requestID, _ := c.Locals("requestid").(string)
+		logger.RequestLog(requestID, c.Method(), c.Path(), c.IP(), c.Response().StatusCode(), duration)
 		return err
 	})
 
-	// Define API endpoints
+	// Define API endpoints. Mutating routes require the "provisioner" role
+	// and above; read-only routes only require "viewer".
 	app.Get("/health", api.HealthHandler)
-	app.Post("/master/provision", api.ProvisionHandler("master"))
-	app.Post("/worker/provision", api.ProvisionHandler("worker"))
-	app.Delete("/vm/:name", api.DeleteVMHandler)
+	app.Post("/master/provision", require(auth.RoleProvisioner), api.ProvisionHandler("master", manager))
+	app.Post("/worker/provision", require(auth.RoleProvisioner), api.ProvisionHandler("worker", manager))
+	app.Post("/provision/bulk", require(auth.RoleProvisioner), api.BulkProvisionHandler(engine))
+	app.Delete("/vm/:name", require(auth.RoleAdmin), api.DeleteVMHandler(repo, prov))
+	app.Get("/jobs", require(auth.RoleViewer), api.ListJobsHandler(manager))
+	app.Get("/jobs/:id", require(auth.RoleViewer), api.GetJobHandler(manager))
+	app.Get("/jobs/:id/events", require(auth.RoleViewer), api.JobEventsHandler(manager))
+
+	app.Post("/clusters", require(auth.RoleProvisioner), api.CreateClusterHandler(clusterManager))
+	app.Get("/clusters/:name", require(auth.RoleViewer), api.GetClusterHandler(clusterManager))
+	app.Post("/clusters/:name/workers", require(auth.RoleProvisioner), api.AddWorkerHandler(clusterManager))
+	app.Delete("/clusters/:name", require(auth.RoleAdmin), api.DeleteClusterHandler(clusterManager))
 
-	// Start server
+	// Start server. mTLS requires the transport itself to request and
+	// verify a client certificate, not just the MTLSAuthenticator that
+	// reads the CN off of it, so serving over ListenMutualTLS is part of
+	// actually enabling MTLS_ROLE_MAP rather than leaving it dead.
 	port := "5090"
-	logger.Info("Starting Ignite API server on port %s...", port)
-	if err := app.Listen(":" + port); err != nil {
-		logger.Fatal("Failed to start server: %v", err)
+	certFile, keyFile, clientCAFile := os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE"), os.Getenv("MTLS_CLIENT_CA_FILE")
+	mtlsConfigured := certFile != "" || keyFile != "" || clientCAFile != ""
+	if mtlsAuth != nil && !mtlsConfigured {
+		logger.Fatal("MTLS_ROLE_MAP is set but TLS_CERT_FILE/TLS_KEY_FILE/MTLS_CLIENT_CA_FILE are not; the server cannot verify client certificates over plain HTTP")
+	}
+	if mtlsConfigured && (mtlsAuth == nil || certFile == "" || keyFile == "" || clientCAFile == "") {
+		logger.Fatal("TLS_CERT_FILE/TLS_KEY_FILE/MTLS_CLIENT_CA_FILE require each other and MTLS_ROLE_MAP to all be set to serve mTLS")
+	}
+	go func() {
+		var err error
+		if mtlsAuth != nil {
+			logger.Info("Starting Ignite API server on port %s (mTLS)...", port)
+			err = app.ListenMutualTLS(":"+port, certFile, keyFile, clientCAFile)
+		} else {
+			logger.Info("Starting Ignite API server on port %s...", port)
+			err = app.Listen(":" + port)
+		}
+		if err != nil {
+			logger.Fatal("Failed to start server: %v", err)
+		}
+	}()
+
+	shutdownTimeout := 30 * time.Second
+	lifecycle.Trap(shutdownTimeout, func(ctx context.Context) {
+		if err := app.ShutdownWithContext(ctx); err != nil {
+			logger.Error("error shutting down http server: %v", err)
+		}
+		if err := manager.Drain(ctx); err != nil {
+			logger.Warn("timed out draining in-flight provisioning jobs: %v", err)
+		}
+		if err := repo.Close(); err != nil {
+			logger.Error("error closing vm store: %v", err)
+		}
+	})
+}
+
+// parseMTLSRoleMap parses MTLS_ROLE_MAP, a comma-separated list of
+// "CommonName=role" pairs, into the CN-to-role map MTLSAuthenticator needs.
+func parseMTLSRoleMap(spec string) map[string]auth.Role {
+	roles := make(map[string]auth.Role)
+	for _, pair := range strings.Split(spec, ",") {
+		cn, role, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		roles[strings.TrimSpace(cn)] = auth.Role(strings.TrimSpace(role))
 	}
+	return roles
 }
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"ignite-api/internal/logger"
+	"ignite-api/internal/models"
+	"ignite-api/internal/provision"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultBulkParallelism = 4
+
+// bulkProvisionRequest is the payload for POST /provision/bulk: a flat list
+// of nodes to provision, bounded by Parallelism concurrent engine calls.
+type bulkProvisionRequest struct {
+	Nodes       []models.ProvisionRequest `json:"nodes"`
+	Parallelism int                       `json:"parallelism"`
+}
+
+// bulkItemResult is one node's outcome, returned either as an element of
+// the response array or as one line of the NDJSON stream.
+type bulkItemResult struct {
+	NodeName string `json:"nodeName"`
+	Success  bool   `json:"success"`
+	NodeID   string `json:"nodeId,omitempty"`
+	MasterIP string `json:"masterIP,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// BulkProvisionHandler handles POST /provision/bulk: it provisions every
+// node in the request directly through the provisioning engine, bypassing
+// internal/jobs' async queue so the handler can return a result per node
+// once everything's done (or stream one as each node finishes). Masters
+// are provisioned first, in request order, so their MasterIP can be
+// back-filled onto worker entries that didn't already specify one; the
+// remaining workers then run concurrently, capped at Parallelism.
+func BulkProvisionHandler(engine *provision.Engine) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID, _ := c.Locals("requestid").(string)
+		ctx := logger.WithRequestID(c.Context(), requestID)
+		log := logger.WithContext(ctx)
+
+		req := new(bulkProvisionRequest)
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request format: " + err.Error(),
+			})
+		}
+		if len(req.Nodes) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "nodes must not be empty",
+			})
+		}
+		parallelism := req.Parallelism
+		if parallelism <= 0 {
+			parallelism = defaultBulkParallelism
+		}
+
+		log.Info("received bulk provision request", "nodes", len(req.Nodes), "parallelism", parallelism)
+
+		stream := strings.Contains(c.Get(fiber.HeaderAccept), "application/x-ndjson")
+		if !stream {
+			results := runBulkProvision(ctx, engine, req.Nodes, parallelism, nil)
+			return c.JSON(fiber.Map{"success": true, "results": results})
+		}
+
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+		c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+			runBulkProvision(ctx, engine, req.Nodes, parallelism, func(result bulkItemResult) {
+				line, err := json.Marshal(result)
+				if err != nil {
+					return
+				}
+				if _, err := w.Write(append(line, '\n')); err != nil {
+					return
+				}
+				w.Flush()
+			})
+		}))
+		return nil
+	}
+}
+
+// runBulkProvision provisions every node, in master-then-worker order, and
+// returns their results in request order. When onResult is non-nil, it is
+// additionally invoked as each node's result becomes available, for NDJSON
+// streaming; the returned slice is still in request order regardless.
+func runBulkProvision(ctx context.Context, engine *provision.Engine, nodes []models.ProvisionRequest, parallelism int, onResult func(bulkItemResult)) []bulkItemResult {
+	results := make([]bulkItemResult, len(nodes))
+
+	// onResult may be called from multiple worker goroutines at once (for
+	// the NDJSON stream); serialize so lines don't interleave on the wire.
+	var emitMu sync.Mutex
+	emit := func(i int, result bulkItemResult) {
+		results[i] = result
+		if onResult != nil {
+			emitMu.Lock()
+			onResult(result)
+			emitMu.Unlock()
+		}
+	}
+
+	// Masters run first and sequentially, since a worker may need the
+	// MasterIP/Token a master in this same batch hasn't produced yet.
+	var masterIP, masterToken string
+	for i, node := range nodes {
+		if node.NodeType == "worker" {
+			continue
+		}
+		result := provisionOne(ctx, engine, node)
+		if result.Success {
+			masterIP, masterToken = result.MasterIP, node.Token
+		}
+		emit(i, result)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, parallelism)
+
+	for i, node := range nodes {
+		if node.NodeType != "worker" {
+			continue
+		}
+		i, node := i, node
+		if node.MasterIP == "" {
+			node.MasterIP, node.Token = masterIP, firstNonEmpty(node.Token, masterToken)
+		}
+
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-gctx.Done():
+				return nil
+			}
+			defer func() { <-sem }()
+
+			emit(i, provisionOne(gctx, engine, node))
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
+
+// provisionOne runs the full engine pipeline for one node, translating a
+// failure into a bulkItemResult rather than an error so one bad node
+// doesn't abort the rest of the batch.
+func provisionOne(ctx context.Context, engine *provision.Engine, node models.ProvisionRequest) bulkItemResult {
+	resp, err := engine.Provision(ctx, node.NodeType, &node)
+	if err != nil {
+		return bulkItemResult{NodeName: node.NodeName, Success: false, Error: err.Error()}
+	}
+	return bulkItemResult{
+		NodeName: node.NodeName,
+		Success:  true,
+		NodeID:   resp.NodeID,
+		MasterIP: resp.MasterIP,
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"ignite-api/internal/models"
+	"ignite-api/internal/provision"
+	"ignite-api/internal/provisioner"
+	"ignite-api/internal/store"
+)
+
+type bulkFakeVMRepo struct{}
+
+func (bulkFakeVMRepo) Create(ctx context.Context, vm store.VM) error { return nil }
+func (bulkFakeVMRepo) Get(ctx context.Context, nodeName string) (*store.VM, error) {
+	return nil, store.ErrNotFound
+}
+func (bulkFakeVMRepo) List(ctx context.Context) ([]store.VM, error)      { return nil, nil }
+func (bulkFakeVMRepo) Delete(ctx context.Context, nodeName string) error { return nil }
+func (bulkFakeVMRepo) FindByMasterIPAndToken(ctx context.Context, masterIP, token string) (*store.VM, error) {
+	return nil, store.ErrNotFound
+}
+func (bulkFakeVMRepo) UpdateStatus(ctx context.Context, nodeName string, status store.Status) error {
+	return nil
+}
+
+// bulkFakeRunner tracks how many Create calls are in flight at once, so
+// tests can assert the semaphore in runBulkProvision actually bounds
+// concurrency, records the order VMs are created in so master-before-worker
+// ordering can be checked, and reports the same IP for every VM.
+type bulkFakeRunner struct {
+	mu          sync.Mutex
+	inFlight    int32
+	maxInFlight int32
+	order       []string
+}
+
+func (r *bulkFakeRunner) Create(ctx context.Context, spec provisioner.VMSpec) (provisioner.VMHandle, error) {
+	n := atomic.AddInt32(&r.inFlight, 1)
+	defer atomic.AddInt32(&r.inFlight, -1)
+
+	r.mu.Lock()
+	if n > r.maxInFlight {
+		r.maxInFlight = n
+	}
+	r.order = append(r.order, spec.Name)
+	r.mu.Unlock()
+
+	return provisioner.VMHandle{Name: spec.Name, UID: spec.UID}, nil
+}
+
+func (r *bulkFakeRunner) Get(ctx context.Context, name string) (provisioner.VMStatus, error) {
+	return provisioner.VMStatus{Name: name, IP: "10.0.0.100", Running: true}, nil
+}
+
+func (r *bulkFakeRunner) Delete(ctx context.Context, name string) error { return nil }
+
+func TestRunBulkProvisionOrdersMastersBeforeWorkersAndBoundsParallelism(t *testing.T) {
+	runner := &bulkFakeRunner{}
+	engine := provision.New(provision.WithStore(bulkFakeVMRepo{}), provision.WithRunner(runner))
+
+	nodes := []models.ProvisionRequest{
+		{NodeName: "worker-1", NodeType: "worker"},
+		{NodeName: "master-1", NodeType: "master"},
+		{NodeName: "worker-2", NodeType: "worker"},
+		{NodeName: "worker-3", NodeType: "worker"},
+	}
+
+	results := runBulkProvision(context.Background(), engine, nodes, 2, nil)
+
+	if len(results) != len(nodes) {
+		t.Fatalf("got %d results, want %d", len(results), len(nodes))
+	}
+	for i, node := range nodes {
+		if results[i].NodeName != node.NodeName {
+			t.Errorf("results[%d].NodeName = %q, want %q (results must stay in request order)", i, results[i].NodeName, node.NodeName)
+		}
+		if !results[i].Success {
+			t.Errorf("results[%d] (%s) did not succeed: %s", i, node.NodeName, results[i].Error)
+		}
+	}
+
+	for i, node := range nodes {
+		if node.NodeType != "worker" {
+			continue
+		}
+		if results[i].MasterIP != "10.0.0.100" {
+			t.Errorf("worker %s got MasterIP %q, want the IP the runner reported (10.0.0.100)", node.NodeName, results[i].MasterIP)
+		}
+	}
+
+	if len(runner.order) == 0 || runner.order[0] != "master-1" {
+		t.Errorf("Create order = %v, want master-1 created before any worker", runner.order)
+	}
+
+	if runner.maxInFlight > 2 {
+		t.Errorf("max concurrent Create calls = %d, want <= parallelism (2)", runner.maxInFlight)
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"ignite-api/internal/auth"
+	"ignite-api/internal/clusters"
+	"ignite-api/internal/config"
+	"ignite-api/internal/logger"
+	"ignite-api/internal/store"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// clusterView is the JSON representation of a cluster returned by the API.
+// JoinToken and Kubeconfig are both cluster-admin-equivalent secrets — the
+// former lets anyone join a worker, the latter is the k3s admin
+// kubeconfig — so toClusterView only includes them for callers whose role
+// allows RoleProvisioner or above; a RoleViewer token gets everything else.
+type clusterView struct {
+	Name           string `json:"name"`
+	MasterNodeName string `json:"masterNodeName"`
+	MasterIP       string `json:"masterIP"`
+	JoinToken      string `json:"joinToken,omitempty"`
+	Kubeconfig     string `json:"kubeconfig,omitempty"`
+	Status         string `json:"status"`
+	CreatedAt      string `json:"createdAt"`
+	UpdatedAt      string `json:"updatedAt"`
+}
+
+func toClusterView(cluster *store.Cluster, includeSecrets bool) clusterView {
+	view := clusterView{
+		Name:           cluster.Name,
+		MasterNodeName: cluster.MasterNodeName,
+		MasterIP:       cluster.MasterIP,
+		Status:         string(cluster.Status),
+		CreatedAt:      cluster.CreatedAt.Format(timeLayout),
+		UpdatedAt:      cluster.UpdatedAt.Format(timeLayout),
+	}
+	if includeSecrets {
+		view.JoinToken = cluster.JoinToken
+		view.Kubeconfig = cluster.Kubeconfig
+	}
+	return view
+}
+
+// CreateClusterHandler handles POST /clusters: provisions a master VM and
+// returns the cluster record, including its fetched kubeconfig.
+func CreateClusterHandler(manager *clusters.Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := new(config.ClusterRequest)
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request format: " + err.Error(),
+			})
+		}
+
+		cluster, err := manager.Create(c.Context(), *req)
+		if err != nil {
+			logger.Error("failed to create cluster %s: %v", req.Name, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"success": true,
+			"cluster": toClusterView(cluster, true),
+		})
+	}
+}
+
+// GetClusterHandler handles GET /clusters/:name. JoinToken and Kubeconfig
+// are only included for callers whose role allows provisioning; a viewer
+// token sees everything else but not the secrets that would let it join a
+// worker or reach the cluster as its k3s admin.
+func GetClusterHandler(manager *clusters.Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		cluster, err := manager.Get(c.Context(), c.Params("name"))
+		if err != nil {
+			if errors.Is(err, store.ErrClusterNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"success": false, "error": "cluster not found"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"success": false, "error": err.Error()})
+		}
+
+		principal, _ := auth.FromFiberCtx(c)
+		includeSecrets := principal.Role.Allows(auth.RoleProvisioner)
+		return c.JSON(fiber.Map{"success": true, "cluster": toClusterView(cluster, includeSecrets)})
+	}
+}
+
+// AddWorkerHandler handles POST /clusters/:name/workers: provisions a
+// worker VM pre-joined to the named cluster. A token scoped to a specific
+// master (ScopedMasterIP) may only join workers to the cluster whose
+// master matches that IP, mirroring ProvisionHandler's scope check.
+func AddWorkerHandler(manager *clusters.Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := new(config.WorkerRequest)
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"success": false,
+				"error":   "Invalid request format: " + err.Error(),
+			})
+		}
+
+		clusterName := c.Params("name")
+		cluster, err := manager.Get(c.Context(), clusterName)
+		if err != nil {
+			if errors.Is(err, store.ErrClusterNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"success": false, "error": "cluster not found"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"success": false, "error": err.Error()})
+		}
+
+		if principal, ok := auth.FromFiberCtx(c); ok && principal.ScopedMasterIP != "" && principal.ScopedMasterIP != cluster.MasterIP {
+			logger.Warn("token scoped to a different master: scoped=%s cluster_master=%s", principal.ScopedMasterIP, cluster.MasterIP)
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"success": false,
+				"error":   "token is scoped to worker-provisioning against a specific master",
+			})
+		}
+
+		vm, err := manager.AddWorker(c.Context(), clusterName, *req)
+		if err != nil {
+			if errors.Is(err, store.ErrClusterNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"success": false, "error": "cluster not found"})
+			}
+			logger.Error("failed to add worker to cluster %s: %v", clusterName, err)
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"success": false, "error": err.Error()})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"success": true,
+			"message": fmt.Sprintf("Worker '%s' joined cluster '%s'", vm.NodeName, clusterName),
+			"vm":      vm,
+		})
+	}
+}
+
+// DeleteClusterHandler handles DELETE /clusters/:name: tears down the
+// master and every worker belonging to the cluster.
+func DeleteClusterHandler(manager *clusters.Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		name := c.Params("name")
+		if err := manager.Delete(c.Context(), name); err != nil {
+			if errors.Is(err, store.ErrClusterNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"success": false, "error": "cluster not found"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"success": false, "error": err.Error()})
+		}
+		return c.JSON(fiber.Map{"success": true, "message": fmt.Sprintf("Cluster '%s' deleted", name)})
+	}
+}
@@ -1,12 +1,14 @@
 package api
 
 import (
+	"errors"
 	"fmt"
-	"ignite-api/internal/config"
+	"ignite-api/internal/auth"
+	"ignite-api/internal/jobs"
+	"ignite-api/internal/logger"
 	"ignite-api/internal/models"
-	"ignite-api/internal/utils"
-	"log"
-	"os"
+	"ignite-api/internal/provisioner"
+	"ignite-api/internal/store"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -19,182 +21,116 @@ func HealthHandler(c *fiber.Ctx) error {
 	})
 }
 
-// ProvisionHandler handles the provision request for nodes
-func ProvisionHandler(nodeType string) fiber.Handler {
+// ProvisionHandler accepts a provision request for nodes, enqueues it onto
+// the job manager, and returns 202 Accepted with a jobId for the caller to
+// poll via GetJobHandler or stream via JobEventsHandler.
+func ProvisionHandler(nodeType string, manager *jobs.Manager) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		requestID, _ := c.Locals("requestid").(string)
+		ctx := logger.WithRequestID(c.Context(), requestID)
+
 		request := new(models.ProvisionRequest)
 		if err := c.BodyParser(request); err != nil {
-			log.Printf("Invalid request format: %v\n", err)
+			logger.WithContext(ctx).Warn("invalid request format", "error", err)
 			return c.Status(400).JSON(models.ProvisionResponse{
 				Success: false,
 				Error:   "Invalid request format: " + err.Error(),
 			})
 		}
 
-		log.Printf("Received %s provision request: %+v\n", nodeType, request)
+		ctx = logger.WithNodeContext(ctx, request.NodeName, request.NodeUID)
+		log := logger.WithContext(ctx)
+		log.Info("received provision request", "node_type", nodeType)
+
+		if principal, ok := auth.FromFiberCtx(c); ok && principal.ScopedMasterIP != "" {
+			if nodeType != "worker" || request.MasterIP != principal.ScopedMasterIP {
+				log.Warn("token scoped to a different master", "scoped_master_ip", principal.ScopedMasterIP)
+				return c.Status(403).JSON(models.ProvisionResponse{
+					Success: false,
+					Error:   "token is scoped to worker-provisioning against a specific master",
+				})
+			}
+		}
 
-		if err := validateProvisionRequest(request, nodeType); err != nil {
-			log.Printf("%v\n", err)
+		if err := manager.Validate(ctx, request, nodeType); err != nil {
+			log.Warn(err.Error())
 			return c.Status(400).JSON(models.ProvisionResponse{
 				Success: false,
 				Error:   err.Error(),
 			})
 		}
 
-		cfg := createConfig(request)
-		manifest := createManifest(request)
-
-		configFileName, err := utils.CreateTempConfigFile(cfg)
+		jobID, err := manager.Enqueue(ctx, nodeType, request)
 		if err != nil {
-			log.Printf("%v\n", err)
-			return c.Status(500).JSON(models.ProvisionResponse{
+			log.Error("failed to enqueue provision job", "error", err)
+			return c.Status(503).JSON(models.ProvisionResponse{
 				Success: false,
 				Error:   err.Error(),
 			})
 		}
-		defer os.Remove(configFileName)
-
-		manifest.Spec.CopyFiles = []struct {
-			HostPath string `yaml:"hostPath"`
-			VMPath   string `yaml:"vmPath"`
-		}{
-			{
-				HostPath: configFileName,
-				VMPath:   "/root/config.json",
-			},
-		}
 
-		manifestFileName, err := utils.CreateTempManifestFile(manifest)
-		if err != nil {
-			log.Printf("%v\n", err)
-			return c.Status(500).JSON(models.ProvisionResponse{
-				Success: false,
-				Error:   err.Error(),
-			})
-		}
-		defer os.Remove(manifestFileName)
+		log.Info("queued provision job", "job_id", jobID)
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"success": true,
+			"jobId":   jobID,
+			"message": fmt.Sprintf("Provisioning of VM '%s' has been queued", request.NodeName),
+		})
+	}
+}
 
-		if err := utils.RunIgnite(manifestFileName, request.NodeName); err != nil {
-			log.Printf("%v\n", err)
-			return c.Status(500).JSON(models.ProvisionResponse{
-				Success: false,
-				Error:   err.Error(),
+// DeleteVMHandler handles the deletion of a VM, dispatching to whichever
+// backend the VM record says it was provisioned on rather than assuming the
+// server's configured default, since a node can have been created with a
+// per-request Backend override.
+func DeleteVMHandler(repo store.VMRepository, prov provisioner.Provisioner) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		vmName := c.Params("name")
+		if vmName == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"error":   "VM name is required",
 			})
 		}
 
-		masterIP, err := utils.GetMasterIP(request.NodeName)
-		if err != nil {
-			log.Printf("Failed to get master IP: %v\n", err)
-			return c.Status(500).JSON(models.ProvisionResponse{
-				Success: false,
-				Error:   "Failed to get master IP: " + err.Error(),
-			})
+		backend := prov
+		if vm, err := repo.Get(c.Context(), vmName); err == nil && vm.Backend != "" {
+			b, err := provisioner.New(vm.Backend)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{
+					"success": false,
+					"error":   fmt.Sprintf("Failed to resolve VM backend: %v", err),
+				})
+			}
+			backend = b
 		}
 
-		if err := utils.StoreProvisionInfo(request.NodeName, request.NodeUID, masterIP, request.NodeType, request.Token); err != nil {
-			log.Printf("Failed to store provision info: %v\n", err)
-			return c.Status(500).JSON(models.ProvisionResponse{
-				Success: false,
-				Error:   "Failed to store provision info: " + err.Error(),
+		if err := backend.Delete(c.Context(), vmName); err != nil {
+			return c.Status(igniteErrorStatus(err)).JSON(fiber.Map{
+				"success": false,
+				"error":   fmt.Sprintf("Failed to delete VM: %v", err),
 			})
 		}
 
-		log.Printf("VM '%s' successfully provisioned with IP %s\n", request.NodeName, masterIP)
-		return c.JSON(models.ProvisionResponse{
-			Success:  true,
-			Message:  fmt.Sprintf("VM '%s' successfully provisioned", request.NodeName),
-			NodeID:   request.NodeUID,
-			MasterIP: masterIP,
-		})
-	}
-}
-
-// DeleteVMHandler handles the deletion of a VM
-func DeleteVMHandler(c *fiber.Ctx) error {
-	vmName := c.Params("name")
-	if vmName == "" {
-		return c.Status(400).JSON(fiber.Map{
-			"success": false,
-			"error":   "VM name is required",
-		})
-	}
-
-	if err := utils.RunIgniteCommand("stop", vmName); err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"success": false,
-			"error":   fmt.Sprintf("Failed to stop VM: %v", err),
-		})
-	}
+		if err := repo.UpdateStatus(c.Context(), vmName, store.StatusDeleted); err != nil && err != store.ErrNotFound {
+			logger.Error("failed to mark vm deleted: %v", err)
+		}
 
-	if err := utils.RunIgniteCommand("rm", vmName); err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"success": false,
-			"error":   fmt.Sprintf("Failed to remove VM: %v", err),
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": fmt.Sprintf("VM '%s' successfully deleted", vmName),
 		})
 	}
-
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": fmt.Sprintf("VM '%s' successfully deleted", vmName),
-	})
-}
-
-// Helper functions
-func validateProvisionRequest(request *models.ProvisionRequest, nodeType string) error {
-	if request.NodeName == "" || request.NodeUID == "" {
-		return fmt.Errorf("NodeName and NodeUID are required fields")
-	}
-	if nodeType == "worker" && (request.MasterIP == "" || request.NodeType != "worker") {
-		return fmt.Errorf("NodeName, NodeUID, MasterIP, and NodeType 'worker' are required fields")
-	}
-	if nodeType == "worker" && !utils.ValidateTokenAndMasterIP(request.Token, request.MasterIP) {
-		return fmt.Errorf("Token and MasterIP do not match any existing records")
-	}
-	return nil
-}
-
-func createConfig(request *models.ProvisionRequest) config.Config {
-	return config.Config{
-		Name:     request.NodeName,
-		UID:      request.NodeUID,
-		NodeType: request.NodeType,
-		Token:    request.Token,
-		MasterIP: request.MasterIP,
-	}
 }
 
-func createManifest(request *models.ProvisionRequest) config.Manifest {
-	manifest := config.Manifest{
-		APIVersion: "ignite.weave.works/v1alpha4",
-		Kind:       "VM",
-	}
-	manifest.Metadata.Name = request.NodeName
-	manifest.Metadata.UID = request.NodeUID
-
-	cpus := request.CPUs
-	if cpus <= 0 {
-		cpus = 2
+// igniteErrorStatus maps a typed backend error onto the HTTP status code that
+// best reflects it, falling back to 500 for anything unclassified.
+func igniteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, provisioner.ErrVMNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, provisioner.ErrImageMissing), errors.Is(err, provisioner.ErrKernelPull):
+		return fiber.StatusUnprocessableEntity
+	default:
+		return fiber.StatusInternalServerError
 	}
-	diskSize := request.DiskSize
-	if diskSize == "" {
-		diskSize = "3GB"
-	}
-	memory := request.Memory
-	if memory == "" {
-		memory = "1GB"
-	}
-	imageOCI := request.ImageOCI
-	if imageOCI == "" {
-		imageOCI = "shajalahamedcse/only-k3-go:v1.0.10"
-	}
-
-	log.Printf("Using image OCI: %s\n", imageOCI)
-
-	manifest.Spec.Image = map[string]string{"oci": imageOCI}
-	manifest.Spec.CPUs = cpus
-	manifest.Spec.DiskSize = diskSize
-	manifest.Spec.Memory = memory
-	manifest.Spec.SSH = request.EnableSSH
-
-	return manifest
 }
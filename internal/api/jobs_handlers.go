@@ -0,0 +1,187 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"ignite-api/internal/jobs"
+	"ignite-api/internal/store"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// jobView is the JSON representation returned for a job. Phase is the
+// detailed provisioning stage; State collapses it to the coarser
+// pending/running/succeeded/failed model callers filtering GET /jobs expect.
+type jobView struct {
+	ID         string `json:"id"`
+	NodeType   string `json:"nodeType"`
+	Phase      string `json:"phase"`
+	State      string `json:"state"`
+	Progress   int    `json:"progress"`
+	Error      string `json:"error,omitempty"`
+	Result     string `json:"result,omitempty"`
+	StartedAt  string `json:"startedAt"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+	UpdatedAt  string `json:"updatedAt"`
+}
+
+const timeLayout = "2006-01-02T15:04:05Z07:00"
+
+func toJobView(job *store.Job) jobView {
+	view := jobView{
+		ID:        job.ID,
+		NodeType:  job.NodeType,
+		Phase:     string(job.Phase),
+		State:     string(classifyJobState(job.Phase)),
+		Progress:  jobProgress(job.Phase),
+		Error:     job.Error,
+		Result:    job.ResultJSON,
+		StartedAt: job.CreatedAt.Format(timeLayout),
+		CreatedAt: job.CreatedAt.Format(timeLayout),
+		UpdatedAt: job.UpdatedAt.Format(timeLayout),
+	}
+	if isTerminalPhase(job.Phase) {
+		view.FinishedAt = job.UpdatedAt.Format(timeLayout)
+	}
+	return view
+}
+
+// jobState collapses store.JobPhase's fine-grained stages onto the
+// pending/running/succeeded/failed model GET /jobs?state= filters against.
+type jobState string
+
+const (
+	jobStatePending   jobState = "pending"
+	jobStateRunning   jobState = "running"
+	jobStateSucceeded jobState = "succeeded"
+	jobStateFailed    jobState = "failed"
+)
+
+func classifyJobState(phase store.JobPhase) jobState {
+	switch phase {
+	case store.JobPhaseQueued:
+		return jobStatePending
+	case store.JobPhaseComplete:
+		return jobStateSucceeded
+	case store.JobPhaseFailed:
+		return jobStateFailed
+	default:
+		return jobStateRunning
+	}
+}
+
+// jobProgress gives callers a rough 0-100 percentage without requiring them
+// to understand every intermediate phase name.
+func jobProgress(phase store.JobPhase) int {
+	switch phase {
+	case store.JobPhaseQueued:
+		return 0
+	case store.JobPhaseWritingManifest:
+		return 20
+	case store.JobPhaseIgniteRun:
+		return 50
+	case store.JobPhaseFetchingIP:
+		return 75
+	case store.JobPhaseStoring:
+		return 90
+	case store.JobPhaseComplete, store.JobPhaseFailed:
+		return 100
+	default:
+		return 0
+	}
+}
+
+// ListJobsHandler returns every job, optionally filtered by the coarse
+// ?state=pending|running|succeeded|failed query param.
+func ListJobsHandler(manager *jobs.Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		all, err := manager.ListJobs(c.Context())
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"success": false, "error": err.Error()})
+		}
+
+		stateFilter := c.Query("state")
+
+		views := make([]jobView, 0, len(all))
+		for i := range all {
+			view := toJobView(&all[i])
+			if stateFilter == "" || view.State == stateFilter {
+				views = append(views, view)
+			}
+		}
+		return c.JSON(fiber.Map{"success": true, "jobs": views})
+	}
+}
+
+// GetJobHandler returns the current phase, state, progress, error and result
+// for a job. The job's captured stdout/stderr is not included.
+func GetJobHandler(manager *jobs.Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		job, err := manager.GetJob(c.Context(), id)
+		if err == store.ErrJobNotFound {
+			return c.Status(404).JSON(fiber.Map{"success": false, "error": "job not found"})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"success": false, "error": err.Error()})
+		}
+		return c.JSON(toJobView(job))
+	}
+}
+
+// JobEventsHandler streams phase transitions for a job as Server-Sent
+// Events, closing once the job reaches a terminal phase. If the job is
+// already terminal when the client connects, it writes that final event
+// immediately instead of waiting on a stream that will never publish.
+func JobEventsHandler(manager *jobs.Manager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		job, err := manager.GetJob(c.Context(), id)
+		if err != nil {
+			if err == store.ErrJobNotFound {
+				return c.Status(404).JSON(fiber.Map{"success": false, "error": "job not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"success": false, "error": err.Error()})
+		}
+
+		c.Set("Content-Type", "text/event-stream")
+		c.Set("Cache-Control", "no-cache")
+		c.Set("Connection", "keep-alive")
+
+		// A job already in a terminal phase will never publish another
+		// event, so subscribing here would leave the connection open
+		// forever with nothing to write. Emit the final state once and
+		// close instead of subscribing to a stream no one will feed.
+		if isTerminalPhase(job.Phase) {
+			c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", job.Phase, job.Error)
+				w.Flush()
+			}))
+			return nil
+		}
+
+		events, unsubscribe := manager.Subscribe(id)
+		c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+			defer unsubscribe()
+
+			for event := range events {
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Phase, event.Message); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+				if isTerminalPhase(event.Phase) {
+					return
+				}
+			}
+		}))
+		return nil
+	}
+}
+
+func isTerminalPhase(phase store.JobPhase) bool {
+	return phase == store.JobPhaseComplete || phase == store.JobPhaseFailed
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"testing"
+
+	"ignite-api/internal/store"
+)
+
+func TestClassifyJobState(t *testing.T) {
+	cases := []struct {
+		phase store.JobPhase
+		want  jobState
+	}{
+		{store.JobPhaseQueued, jobStatePending},
+		{store.JobPhaseWritingManifest, jobStateRunning},
+		{store.JobPhaseIgniteRun, jobStateRunning},
+		{store.JobPhaseFetchingIP, jobStateRunning},
+		{store.JobPhaseStoring, jobStateRunning},
+		{store.JobPhaseComplete, jobStateSucceeded},
+		{store.JobPhaseFailed, jobStateFailed},
+	}
+
+	for _, tc := range cases {
+		if got := classifyJobState(tc.phase); got != tc.want {
+			t.Errorf("classifyJobState(%q) = %q, want %q", tc.phase, got, tc.want)
+		}
+	}
+}
+
+func TestToJobView(t *testing.T) {
+	job := &store.Job{
+		ID:       "job-1",
+		NodeType: "worker",
+		Phase:    store.JobPhaseComplete,
+	}
+
+	view := toJobView(job)
+	if view.State != string(jobStateSucceeded) {
+		t.Errorf("view.State = %q, want %q", view.State, jobStateSucceeded)
+	}
+	if view.FinishedAt == "" {
+		t.Error("expected FinishedAt to be set for a terminal phase")
+	}
+}
@@ -0,0 +1,48 @@
+// Package auth authenticates incoming requests and enforces role-based
+// access control in front of the provisioning API. How a caller proves its
+// identity (a static bearer token, a JWT, or an mTLS client certificate) is
+// pluggable via the Authenticator interface; the Role model underneath is
+// shared no matter which authenticator validated the request.
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// Role is a coarse-grained permission level enforced per route.
+type Role string
+
+const (
+	RoleAdmin       Role = "admin"       // can create, delete, and list all VMs
+	RoleProvisioner Role = "provisioner" // can create VMs
+	RoleViewer      Role = "viewer"      // read-only
+)
+
+var roleRank = map[Role]int{RoleViewer: 0, RoleProvisioner: 1, RoleAdmin: 2}
+
+// Allows reports whether a caller holding r may perform an action that
+// requires the required role.
+func (r Role) Allows(required Role) bool {
+	return roleRank[r] >= roleRank[required]
+}
+
+// Principal is the authenticated identity attached to a request once an
+// Authenticator has validated its credential.
+type Principal struct {
+	Subject string
+	Role    Role
+	// ScopedMasterIP, when non-empty, restricts a provisioner token to
+	// worker-provisioning requests targeting that one master.
+	ScopedMasterIP string
+}
+
+// ErrUnauthenticated is returned by an Authenticator when no valid
+// credential was presented.
+var ErrUnauthenticated = errors.New("auth: unauthenticated")
+
+// Authenticator validates an opaque credential string (a bearer token or a
+// JWT) and returns the Principal it represents.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (Principal, error)
+}
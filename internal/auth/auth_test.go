@@ -0,0 +1,26 @@
+package auth
+
+import "testing"
+
+func TestRoleAllows(t *testing.T) {
+	cases := []struct {
+		held     Role
+		required Role
+		want     bool
+	}{
+		{RoleAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleProvisioner, true},
+		{RoleAdmin, RoleViewer, true},
+		{RoleProvisioner, RoleAdmin, false},
+		{RoleProvisioner, RoleProvisioner, true},
+		{RoleProvisioner, RoleViewer, true},
+		{RoleViewer, RoleProvisioner, false},
+		{RoleViewer, RoleViewer, true},
+	}
+
+	for _, tc := range cases {
+		if got := tc.held.Allows(tc.required); got != tc.want {
+			t.Errorf("Role(%q).Allows(%q) = %v, want %v", tc.held, tc.required, got, tc.want)
+		}
+	}
+}
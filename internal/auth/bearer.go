@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"ignite-api/internal/store"
+)
+
+// BearerAuthenticator validates opaque API tokens persisted in the store.
+// Only the SHA-256 hash of a token is ever stored or compared, so a leaked
+// database dump doesn't hand out live credentials.
+type BearerAuthenticator struct {
+	tokens store.TokenRepository
+}
+
+// NewBearerAuthenticator builds a BearerAuthenticator backed by tokens.
+func NewBearerAuthenticator(tokens store.TokenRepository) *BearerAuthenticator {
+	return &BearerAuthenticator{tokens: tokens}
+}
+
+// Authenticate looks up credential (the raw bearer token) by its hash.
+func (a *BearerAuthenticator) Authenticate(ctx context.Context, credential string) (Principal, error) {
+	if credential == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+
+	tok, err := a.tokens.GetTokenByHash(ctx, HashToken(credential))
+	if err != nil {
+		if errors.Is(err, store.ErrTokenNotFound) {
+			return Principal{}, ErrUnauthenticated
+		}
+		return Principal{}, err
+	}
+
+	return Principal{
+		Subject:        tok.Name,
+		Role:           Role(tok.Role),
+		ScopedMasterIP: tok.ScopedMasterIP,
+	}, nil
+}
+
+// HashToken returns the stored-comparison form of a raw bearer token.
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ignite-api/internal/store"
+)
+
+type fakeTokenRepo struct {
+	byHash map[string]store.APIToken
+}
+
+func newFakeTokenRepo() *fakeTokenRepo {
+	return &fakeTokenRepo{byHash: make(map[string]store.APIToken)}
+}
+
+func (f *fakeTokenRepo) CreateToken(ctx context.Context, token store.APIToken) error {
+	f.byHash[token.HashedSecret] = token
+	return nil
+}
+
+func (f *fakeTokenRepo) GetTokenByHash(ctx context.Context, hashedSecret string) (*store.APIToken, error) {
+	tok, ok := f.byHash[hashedSecret]
+	if !ok || tok.Revoked {
+		return nil, store.ErrTokenNotFound
+	}
+	return &tok, nil
+}
+
+func (f *fakeTokenRepo) ListTokens(ctx context.Context) ([]store.APIToken, error) {
+	var all []store.APIToken
+	for _, tok := range f.byHash {
+		all = append(all, tok)
+	}
+	return all, nil
+}
+
+func (f *fakeTokenRepo) RevokeToken(ctx context.Context, id string) error {
+	for hash, tok := range f.byHash {
+		if tok.ID == id {
+			tok.Revoked = true
+			f.byHash[hash] = tok
+			return nil
+		}
+	}
+	return store.ErrTokenNotFound
+}
+
+func TestBearerAuthenticatorRoundTripsScopedMasterIP(t *testing.T) {
+	repo := newFakeTokenRepo()
+	raw := "test-raw-token"
+	if err := repo.CreateToken(context.Background(), store.APIToken{
+		ID:             "tok-1",
+		HashedSecret:   HashToken(raw),
+		Role:           string(RoleProvisioner),
+		ScopedMasterIP: "10.0.0.1",
+	}); err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+
+	authr := NewBearerAuthenticator(repo)
+	principal, err := authr.Authenticate(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if principal.Role != RoleProvisioner {
+		t.Errorf("principal.Role = %q, want %q", principal.Role, RoleProvisioner)
+	}
+	if principal.ScopedMasterIP != "10.0.0.1" {
+		t.Errorf("principal.ScopedMasterIP = %q, want %q", principal.ScopedMasterIP, "10.0.0.1")
+	}
+}
+
+func TestBearerAuthenticatorRejectsUnknownToken(t *testing.T) {
+	authr := NewBearerAuthenticator(newFakeTokenRepo())
+	_, err := authr.Authenticate(context.Background(), "not-a-real-token")
+	if !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate error = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestBearerAuthenticatorRejectsRevokedToken(t *testing.T) {
+	repo := newFakeTokenRepo()
+	raw := "revoked-token"
+	_ = repo.CreateToken(context.Background(), store.APIToken{
+		ID:           "tok-2",
+		HashedSecret: HashToken(raw),
+		Role:         string(RoleAdmin),
+		Revoked:      true,
+	})
+
+	authr := NewBearerAuthenticator(repo)
+	_, err := authr.Authenticate(context.Background(), raw)
+	if !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Authenticate error = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestBootstrapAdminTokenIsIdempotent(t *testing.T) {
+	repo := newFakeTokenRepo()
+	ctx := context.Background()
+
+	if err := BootstrapAdminToken(ctx, repo, "bootstrap-raw"); err != nil {
+		t.Fatalf("first BootstrapAdminToken: %v", err)
+	}
+	if got := len(repo.byHash); got != 1 {
+		t.Fatalf("expected 1 token after bootstrap, got %d", got)
+	}
+
+	if err := BootstrapAdminToken(ctx, repo, "bootstrap-raw"); err != nil {
+		t.Fatalf("second BootstrapAdminToken: %v", err)
+	}
+	if got := len(repo.byHash); got != 1 {
+		t.Errorf("expected bootstrap to stay idempotent, got %d tokens", got)
+	}
+
+	authr := NewBearerAuthenticator(repo)
+	principal, err := authr.Authenticate(ctx, "bootstrap-raw")
+	if err != nil {
+		t.Fatalf("Authenticate bootstrap token: %v", err)
+	}
+	if principal.Role != RoleAdmin {
+		t.Errorf("bootstrap token role = %q, want %q", principal.Role, RoleAdmin)
+	}
+}
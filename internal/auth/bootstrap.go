@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ignite-api/internal/store"
+
+	"github.com/google/uuid"
+)
+
+// BootstrapAdminToken ensures rawToken is valid as an admin credential,
+// inserting it into tokens if no existing token already hashes to it. Every
+// mutating route is behind Require, so without this there would be no way
+// to mint the first token: an operator sets rawToken (e.g. from an env var
+// read once at startup) and uses it to authenticate until further tokens
+// are issued and this one is revoked.
+func BootstrapAdminToken(ctx context.Context, tokens store.TokenRepository, rawToken string) error {
+	if rawToken == "" {
+		return nil
+	}
+
+	hashed := HashToken(rawToken)
+	if _, err := tokens.GetTokenByHash(ctx, hashed); err == nil {
+		return nil
+	} else if !errors.Is(err, store.ErrTokenNotFound) {
+		return fmt.Errorf("failed to check for existing bootstrap token: %w", err)
+	}
+
+	if err := tokens.CreateToken(ctx, store.APIToken{
+		ID:           uuid.NewString(),
+		Name:         "bootstrap-admin",
+		HashedSecret: hashed,
+		Role:         string(RoleAdmin),
+	}); err != nil {
+		return fmt.Errorf("failed to create bootstrap admin token: %w", err)
+	}
+	return nil
+}
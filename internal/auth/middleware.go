@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"ignite-api/internal/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const principalLocalsKey = "principal"
+
+// Require returns Fiber middleware that authenticates the request — trying
+// mtls (if set) via the connection's TLS state, then each of auths in order
+// against the Authorization header — and rejects it unless the resulting
+// Principal's role allows required. Every mutating call's outcome (subject,
+// action, target, allowed/denied/forbidden) is audit-logged regardless of
+// the result.
+func Require(required Role, mtls *MTLSAuthenticator, auths ...Authenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, err := authenticate(c, mtls, auths)
+		if err != nil {
+			audit(c, principal, "denied", err.Error())
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"success": false, "error": "unauthorized"})
+		}
+		if !principal.Role.Allows(required) {
+			audit(c, principal, "forbidden", fmt.Sprintf("role %q lacks %q", principal.Role, required))
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"success": false, "error": "forbidden"})
+		}
+
+		c.Locals(principalLocalsKey, principal)
+		if required != RoleViewer {
+			audit(c, principal, "allowed", "")
+		}
+		return c.Next()
+	}
+}
+
+func authenticate(c *fiber.Ctx, mtls *MTLSAuthenticator, auths []Authenticator) (Principal, error) {
+	if mtls != nil {
+		if tlsConn, ok := c.Context().Conn().(interface{ ConnectionState() tls.ConnectionState }); ok {
+			state := tlsConn.ConnectionState()
+			if p, err := mtls.AuthenticateCert(&state); err == nil {
+				return p, nil
+			}
+		}
+	}
+
+	credential := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+	if credential == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+
+	lastErr := error(ErrUnauthenticated)
+	for _, a := range auths {
+		p, err := a.Authenticate(c.Context(), credential)
+		if err == nil {
+			return p, nil
+		}
+		lastErr = err
+	}
+	return Principal{}, lastErr
+}
+
+// FromFiberCtx returns the Principal Require attached to c, if any.
+func FromFiberCtx(c *fiber.Ctx) (Principal, bool) {
+	p, ok := c.Locals(principalLocalsKey).(Principal)
+	return p, ok
+}
+
+// audit emits a structured log entry for one authentication/authorization
+// decision, through the same structured logger the rest of the server uses.
+// Require calls it for every denied or forbidden request and for allowed
+// requests on routes that need more than RoleViewer.
+func audit(c *fiber.Ctx, principal Principal, outcome, reason string) {
+	subject := principal.Subject
+	if subject == "" {
+		subject = "anonymous"
+	}
+	logger.WithContext(c.Context()).Info("auth decision",
+		"subject", subject,
+		"role", string(principal.Role),
+		"action", c.Method(),
+		"target", c.Path(),
+		"outcome", outcome,
+		"reason", reason,
+	)
+}
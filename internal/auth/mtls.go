@@ -0,0 +1,32 @@
+package auth
+
+import "crypto/tls"
+
+// MTLSAuthenticator derives the caller's identity from its client
+// certificate's Common Name. It trusts whatever tls.Config the server was
+// started with to have already verified the certificate chain; this type
+// only maps a verified CN onto a Role.
+type MTLSAuthenticator struct {
+	roleForCN map[string]Role
+}
+
+// NewMTLSAuthenticator builds an MTLSAuthenticator from a CN-to-role map.
+func NewMTLSAuthenticator(roleForCN map[string]Role) *MTLSAuthenticator {
+	return &MTLSAuthenticator{roleForCN: roleForCN}
+}
+
+// AuthenticateCert maps a verified client certificate onto a Principal.
+// Unlike the other authenticators, mTLS identity comes from the TLS
+// handshake rather than a request header, so it takes the connection state
+// directly instead of a credential string.
+func (a *MTLSAuthenticator) AuthenticateCert(state *tls.ConnectionState) (Principal, error) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return Principal{}, ErrUnauthenticated
+	}
+	cn := state.PeerCertificates[0].Subject.CommonName
+	role, ok := a.roleForCN[cn]
+	if !ok {
+		return Principal{}, ErrUnauthenticated
+	}
+	return Principal{Subject: cn, Role: role}, nil
+}
@@ -0,0 +1,221 @@
+// Package clusters orchestrates multi-VM k3s clusters: a cluster owns one
+// master and N workers that share a generated join token, replacing the
+// fragile pattern of manually correlating master IPs and tokens across
+// individually-provisioned VMs. Master creation fetches the k3s kubeconfig
+// over the provisioner's Exec channel (SSH, for the ignite backend) and
+// rewrites its server URL to the master's reachable IP.
+package clusters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"ignite-api/internal/config"
+	"ignite-api/internal/logger"
+	"ignite-api/internal/provisioner"
+	"ignite-api/internal/store"
+
+	"github.com/google/uuid"
+)
+
+const defaultImageOCI = "shajalahamedcse/only-k3-go:v1.0.10"
+
+// Manager creates and tears down clusters, provisioning VMs directly
+// through a Provisioner rather than the async job queue, since cluster
+// creation must fetch the master's kubeconfig as soon as it boots.
+type Manager struct {
+	clusters    store.ClusterRepository
+	vms         store.VMRepository
+	provisioner provisioner.Provisioner
+}
+
+// NewManager builds a cluster Manager.
+func NewManager(clusters store.ClusterRepository, vms store.VMRepository, prov provisioner.Provisioner) *Manager {
+	return &Manager{clusters: clusters, vms: vms, provisioner: prov}
+}
+
+// Create provisions a new master VM, fetches its kubeconfig, and persists
+// the cluster record plus the master's VM record.
+func (m *Manager) Create(ctx context.Context, req config.ClusterRequest) (*store.Cluster, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("cluster name is required")
+	}
+	if _, err := m.clusters.GetCluster(ctx, req.Name); err == nil {
+		return nil, fmt.Errorf("cluster %q already exists", req.Name)
+	} else if err != store.ErrClusterNotFound {
+		return nil, err
+	}
+
+	masterNodeName := req.Name + "-master"
+	masterUID := orUUID(req.Master.NodeUID)
+	joinToken := uuid.NewString()
+
+	spec := provisioner.VMSpec{
+		Name:      masterNodeName,
+		UID:       masterUID,
+		CPUs:      intOrDefault(req.Master.CPUs, 2),
+		DiskSize:  strOrDefault(req.Master.DiskSize, "3GB"),
+		Memory:    strOrDefault(req.Master.Memory, "1GB"),
+		ImageOCI:  strOrDefault(req.Master.ImageOCI, defaultImageOCI),
+		EnableSSH: true,
+	}
+	if _, err := m.provisioner.Create(ctx, spec); err != nil {
+		return nil, fmt.Errorf("failed to provision cluster master: %w", err)
+	}
+
+	status, err := m.provisioner.Get(ctx, masterNodeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master IP: %w", err)
+	}
+
+	kubeconfig, err := m.fetchKubeconfig(ctx, masterNodeName, status.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := m.vms.Create(ctx, store.VM{
+		NodeName:    masterNodeName,
+		NodeUID:     masterUID,
+		MasterIP:    status.IP,
+		NodeType:    "master",
+		Token:       joinToken,
+		ImageOCI:    spec.ImageOCI,
+		ClusterName: req.Name,
+		Status:      store.StatusReady,
+		Kubeconfig:  kubeconfig,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to store master vm record: %w", err)
+	}
+
+	cluster := store.Cluster{
+		Name:           req.Name,
+		MasterNodeName: masterNodeName,
+		MasterIP:       status.IP,
+		JoinToken:      joinToken,
+		Kubeconfig:     kubeconfig,
+		Status:         store.ClusterStatusReady,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := m.clusters.CreateCluster(ctx, cluster); err != nil {
+		return nil, fmt.Errorf("failed to store cluster record: %w", err)
+	}
+	return &cluster, nil
+}
+
+// AddWorker provisions a worker VM and joins it to an existing cluster,
+// injecting the cluster's join token and master IP automatically.
+func (m *Manager) AddWorker(ctx context.Context, clusterName string, req config.WorkerRequest) (*store.VM, error) {
+	cluster, err := m.clusters.GetCluster(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	workerNodeName := fmt.Sprintf("%s-worker-%s", clusterName, uuid.NewString()[:8])
+	workerUID := orUUID(req.Worker.NodeUID)
+
+	spec := provisioner.VMSpec{
+		Name:     workerNodeName,
+		UID:      workerUID,
+		CPUs:     intOrDefault(req.Worker.CPUs, 2),
+		DiskSize: strOrDefault(req.Worker.DiskSize, "3GB"),
+		Memory:   strOrDefault(req.Worker.Memory, "1GB"),
+		ImageOCI: strOrDefault(req.Worker.ImageOCI, defaultImageOCI),
+	}
+	if _, err := m.provisioner.Create(ctx, spec); err != nil {
+		return nil, fmt.Errorf("failed to provision cluster worker: %w", err)
+	}
+
+	now := time.Now().UTC()
+	vm := store.VM{
+		NodeName:    workerNodeName,
+		NodeUID:     workerUID,
+		MasterIP:    cluster.MasterIP,
+		NodeType:    "worker",
+		Token:       cluster.JoinToken,
+		ImageOCI:    spec.ImageOCI,
+		ClusterName: clusterName,
+		Status:      store.StatusReady,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := m.vms.Create(ctx, vm); err != nil {
+		return nil, fmt.Errorf("failed to store worker vm record: %w", err)
+	}
+	return &vm, nil
+}
+
+// Get returns the cluster record for name.
+func (m *Manager) Get(ctx context.Context, name string) (*store.Cluster, error) {
+	return m.clusters.GetCluster(ctx, name)
+}
+
+// Delete tears down every VM belonging to the cluster, then removes the
+// cluster record itself.
+func (m *Manager) Delete(ctx context.Context, name string) error {
+	if _, err := m.clusters.GetCluster(ctx, name); err != nil {
+		return err
+	}
+
+	vms, err := m.vms.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list vms for cluster teardown: %w", err)
+	}
+	for _, vm := range vms {
+		if vm.ClusterName != name {
+			continue
+		}
+		if err := m.provisioner.Delete(ctx, vm.NodeName); err != nil {
+			logger.Error("failed to delete cluster vm %s: %v", vm.NodeName, err)
+		}
+		if err := m.vms.Delete(ctx, vm.NodeName); err != nil {
+			logger.Error("failed to remove vm record %s: %v", vm.NodeName, err)
+		}
+	}
+
+	return m.clusters.DeleteCluster(ctx, name)
+}
+
+// fetchKubeconfig reads /etc/rancher/k3s/k3s.yaml from the master over the
+// provisioner's Exec channel and rewrites its server URL (which k3s points
+// at localhost by default) to the master's reachable IP.
+func (m *Manager) fetchKubeconfig(ctx context.Context, masterNodeName, masterIP string) (string, error) {
+	out, err := m.provisioner.Exec(ctx, masterNodeName, []string{"cat", "/etc/rancher/k3s/k3s.yaml"})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch kubeconfig: %w", err)
+	}
+	defer out.Close()
+
+	raw, err := io.ReadAll(out)
+	if err != nil {
+		return "", fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+	return strings.ReplaceAll(string(raw), "127.0.0.1", masterIP), nil
+}
+
+func orUUID(v string) string {
+	if v == "" {
+		return uuid.NewString()
+	}
+	return v
+}
+
+func intOrDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func strOrDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
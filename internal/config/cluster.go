@@ -0,0 +1,29 @@
+// Package config holds request/domain types for resources that span more
+// than one provisioned VM, starting with Cluster: a master plus N workers
+// that share a join token and are managed as a single unit instead of
+// separate, manually-correlated provision calls.
+package config
+
+// NodeSpec is the subset of VM sizing/image fields a cluster create or
+// worker-join request can override; zero values fall back to the same
+// defaults ProvisionRequest uses.
+type NodeSpec struct {
+	NodeUID  string `json:"nodeUid"`
+	CPUs     int    `json:"cpus"`
+	DiskSize string `json:"diskSize"`
+	Memory   string `json:"memory"`
+	ImageOCI string `json:"imageOci"`
+}
+
+// ClusterRequest is the payload for POST /clusters.
+type ClusterRequest struct {
+	Name   string   `json:"name"`
+	Master NodeSpec `json:"master"`
+}
+
+// WorkerRequest is the payload for POST /clusters/{name}/workers. Unlike the
+// legacy /worker/provision payload, it carries no masterIP or token: the
+// server looks both up from the named cluster.
+type WorkerRequest struct {
+	Worker NodeSpec `json:"worker"`
+}
@@ -0,0 +1,57 @@
+package config
+
+// File is one file a caller wants written to a provisioned node, following
+// Ignition's storage/files entry shape (see
+// https://coreos.github.io/ignition/configuration-v3_4/) even though no
+// backend in this server actually interprets Ignition: internal/provision
+// decodes Contents itself and delivers Path via VMSpec.CopyFiles like every
+// other node file.
+type File struct {
+	Path     string       `json:"path"`
+	Mode     int          `json:"mode,omitempty"`
+	Contents FileContents `json:"contents"`
+}
+
+// FileContents is the inline "source" form of Ignition's storage/files
+// contents schema: a data: URL carrying a base64 payload.
+type FileContents struct {
+	Source string `json:"source"`
+}
+
+// Unit is one systemd unit a caller wants installed on a provisioned node,
+// following Ignition's systemd/units entry shape. Contents replaces the
+// unit file wholesale; Dropins layer additional config over whatever unit
+// already exists in the image.
+type Unit struct {
+	Name     string   `json:"name"`
+	Enabled  *bool    `json:"enabled,omitempty"`
+	Contents string   `json:"contents,omitempty"`
+	Dropins  []Dropin `json:"dropins,omitempty"`
+}
+
+// Dropin is one systemd unit drop-in, written alongside the named unit.
+type Dropin struct {
+	Name     string `json:"name"`
+	Contents string `json:"contents,omitempty"`
+}
+
+// NodeIdentity is the information a node needs to discover itself and, for
+// workers, the master to join. It is marshaled as-is and delivered to
+// /root/config.json, the flat JSON shape node boot scripts have always read.
+type NodeIdentity struct {
+	Name     string `json:"name"`
+	UID      string `json:"uid"`
+	NodeType string `json:"nodeType"`
+	Token    string `json:"token"`
+	MasterIP string `json:"masterIP"`
+}
+
+// IgnitionOverrides lets a ProvisionRequest extend the files a node is
+// provisioned with beyond its identity: SSH keys, systemd units, and extra
+// files, all delivered via the same host-to-guest file copy every backend
+// already performs.
+type IgnitionOverrides struct {
+	SSHKeys    []string `json:"sshKeys,omitempty"`
+	ExtraUnits []Unit   `json:"extraUnits,omitempty"`
+	ExtraFiles []File   `json:"extraFiles,omitempty"`
+}
@@ -0,0 +1,267 @@
+// Package jobs runs VM provisioning asynchronously so HTTP handlers can
+// return as soon as a request is queued instead of blocking on `ignite run`,
+// which can take minutes. Work is held in a buffered channel and drained by
+// a bounded worker pool; progress is persisted via store.JobRepository so a
+// GET /jobs/{id} poll (or an SSE subscriber) reflects the true current phase
+// even across a server restart. The provisioning steps themselves live in
+// internal/provision.Engine; this package is only responsible for queueing,
+// phase bookkeeping, and fan-out to SSE subscribers.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"ignite-api/internal/logger"
+	"ignite-api/internal/models"
+	"ignite-api/internal/provision"
+	"ignite-api/internal/store"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Event is one phase transition emitted while a job runs, delivered to
+// GET /jobs/{id}/events subscribers.
+type Event struct {
+	JobID   string
+	Phase   store.JobPhase
+	Message string
+}
+
+// Manager queues provisioning work and executes it on a bounded worker pool.
+type Manager struct {
+	engine *provision.Engine
+	jobs   store.JobRepository
+	queue  chan string
+	active sync.WaitGroup
+
+	subsMu sync.Mutex
+	subs   map[string][]chan Event
+}
+
+// NewManager starts maxConcurrency workers pulling off an internally
+// buffered queue so we don't try to boot dozens of VMs at once.
+func NewManager(engine *provision.Engine, jobRepo store.JobRepository, maxConcurrency int) *Manager {
+	if maxConcurrency <= 0 {
+		maxConcurrency = 4
+	}
+	m := &Manager{
+		engine: engine,
+		jobs:   jobRepo,
+		queue:  make(chan string, 256),
+		subs:   make(map[string][]chan Event),
+	}
+	for i := 0; i < maxConcurrency; i++ {
+		go m.worker()
+	}
+	return m
+}
+
+// Resume re-queues jobs left in JobPhaseQueued (nothing had started yet, so
+// they're safe to retry as-is) and fails jobs caught mid-provisioning by a
+// restart: an interrupted writing_manifest/ignite_run/fetching_ip/storing
+// phase may have already booted a VM or left one half-persisted, so
+// silently re-running process() from scratch risks duplicating or
+// conflicting with that work. Callers should invoke this once at startup,
+// right after NewManager, before any new requests are enqueued.
+func (m *Manager) Resume(ctx context.Context) error {
+	all, err := m.jobs.ListJobs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list jobs to resume: %w", err)
+	}
+
+	for _, job := range all {
+		switch job.Phase {
+		case store.JobPhaseComplete, store.JobPhaseFailed:
+			continue
+		case store.JobPhaseQueued:
+			select {
+			case m.queue <- job.ID:
+				logger.Info("re-queued job %s after restart", job.ID)
+			default:
+				logger.Warn("provisioning queue full while resuming job %s; leaving it queued", job.ID)
+			}
+		default:
+			logger.Warn("job %s was interrupted mid-provisioning during phase %s; marking failed", job.ID, job.Phase)
+			m.fail(ctx, job.ID, fmt.Errorf("interrupted by server restart during phase %q", job.Phase))
+		}
+	}
+	return nil
+}
+
+// Validate runs the engine's pre-provisioning checks so handlers can reject
+// a bad request with 400 before it's queued, instead of enqueueing
+// something guaranteed to fail.
+func (m *Manager) Validate(ctx context.Context, request *models.ProvisionRequest, nodeType string) error {
+	return m.engine.Validate(ctx, request, nodeType)
+}
+
+// Enqueue persists a queued job for request and schedules it for execution,
+// returning the job ID the caller should poll or subscribe to.
+func (m *Manager) Enqueue(ctx context.Context, nodeType string, request *models.ProvisionRequest) (string, error) {
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal provision request: %w", err)
+	}
+
+	id := uuid.NewString()
+	if err := m.jobs.CreateJob(ctx, store.Job{
+		ID:          id,
+		NodeType:    nodeType,
+		RequestJSON: string(requestJSON),
+		Phase:       store.JobPhaseQueued,
+	}); err != nil {
+		return "", err
+	}
+
+	select {
+	case m.queue <- id:
+	default:
+		return "", fmt.Errorf("provisioning queue is full, try again later")
+	}
+	return id, nil
+}
+
+// GetJob returns the current persisted state of a job.
+func (m *Manager) GetJob(ctx context.Context, id string) (*store.Job, error) {
+	return m.jobs.GetJob(ctx, id)
+}
+
+// ListJobs returns every persisted job, most recently created first.
+func (m *Manager) ListJobs(ctx context.Context) ([]store.Job, error) {
+	return m.jobs.ListJobs(ctx)
+}
+
+// Subscribe registers for phase-transition events on jobID. Callers must
+// invoke the returned unsubscribe func when done listening.
+func (m *Manager) Subscribe(jobID string) (events <-chan Event, unsubscribe func()) {
+	ch := make(chan Event, 16)
+	m.subsMu.Lock()
+	m.subs[jobID] = append(m.subs[jobID], ch)
+	m.subsMu.Unlock()
+
+	return ch, func() {
+		m.subsMu.Lock()
+		defer m.subsMu.Unlock()
+		subs := m.subs[jobID]
+		for i, c := range subs {
+			if c == ch {
+				m.subs[jobID] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}
+}
+
+func (m *Manager) publish(jobID string, phase store.JobPhase, message string) {
+	m.subsMu.Lock()
+	subs := append([]chan Event(nil), m.subs[jobID]...)
+	m.subsMu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- Event{JobID: jobID, Phase: phase, Message: message}:
+		default:
+			logger.Warn("dropping job event for %s: subscriber is not keeping up", jobID)
+		}
+	}
+}
+
+func (m *Manager) worker() {
+	for id := range m.queue {
+		m.active.Add(1)
+		m.process(id)
+		m.active.Done()
+	}
+}
+
+// Drain blocks until every job currently being processed finishes, or ctx
+// is done first — whichever comes first. It does not stop new jobs already
+// queued from starting; callers should stop enqueueing before draining.
+func (m *Manager) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		m.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Manager) process(id string) {
+	ctx := context.Background()
+	job, err := m.jobs.GetJob(ctx, id)
+	if err != nil {
+		logger.Error("job %s vanished before processing: %v", id, err)
+		return
+	}
+
+	var request models.ProvisionRequest
+	if err := json.Unmarshal([]byte(job.RequestJSON), &request); err != nil {
+		m.fail(ctx, id, fmt.Errorf("failed to decode job request: %w", err))
+		return
+	}
+
+	m.transition(ctx, id, store.JobPhaseWritingManifest)
+	spec, runner, cleanup, err := m.engine.BuildSpec(&request)
+	if err != nil {
+		m.fail(ctx, id, err)
+		return
+	}
+	defer cleanup()
+
+	m.transition(ctx, id, store.JobPhaseIgniteRun)
+	if _, err := m.engine.Create(ctx, runner, spec); err != nil {
+		m.fail(ctx, id, err)
+		return
+	}
+
+	m.transition(ctx, id, store.JobPhaseFetchingIP)
+	status, err := m.engine.Status(ctx, runner, request.NodeName)
+	if err != nil {
+		m.fail(ctx, id, fmt.Errorf("failed to get master IP: %w", err))
+		return
+	}
+
+	m.transition(ctx, id, store.JobPhaseStoring)
+	if err := m.engine.Persist(ctx, runner, &request, spec, status); err != nil {
+		m.fail(ctx, id, err)
+		return
+	}
+
+	result := models.ProvisionResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("VM '%s' successfully provisioned", request.NodeName),
+		NodeID:   request.NodeUID,
+		MasterIP: status.IP,
+	}
+	resultJSON, _ := json.Marshal(result)
+	if err := m.jobs.SetJobResult(ctx, id, string(resultJSON)); err != nil {
+		logger.Error("failed to persist result for job %s: %v", id, err)
+	}
+
+	logger.Info("job %s complete: VM '%s' provisioned with IP %s", id, request.NodeName, status.IP)
+	m.transition(ctx, id, store.JobPhaseComplete)
+}
+
+func (m *Manager) transition(ctx context.Context, id string, phase store.JobPhase) {
+	if err := m.jobs.UpdateJobPhase(ctx, id, phase, ""); err != nil {
+		logger.Error("failed to persist job %s phase %s: %v", id, phase, err)
+	}
+	m.publish(id, phase, "")
+}
+
+func (m *Manager) fail(ctx context.Context, id string, err error) {
+	logger.Error("job %s failed: %v", id, err)
+	if dbErr := m.jobs.UpdateJobPhase(ctx, id, store.JobPhaseFailed, err.Error()); dbErr != nil {
+		logger.Error("failed to persist failure for job %s: %v", id, dbErr)
+	}
+	m.publish(id, store.JobPhaseFailed, err.Error())
+}
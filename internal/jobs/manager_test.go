@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"ignite-api/internal/provision"
+	"ignite-api/internal/provisioner"
+	"ignite-api/internal/store"
+)
+
+type fakeJobRepo struct {
+	mu   sync.Mutex
+	jobs map[string]store.Job
+}
+
+func newFakeJobRepo() *fakeJobRepo {
+	return &fakeJobRepo{jobs: make(map[string]store.Job)}
+}
+
+func (f *fakeJobRepo) CreateJob(ctx context.Context, job store.Job) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.jobs[job.ID] = job
+	return nil
+}
+
+func (f *fakeJobRepo) GetJob(ctx context.Context, id string) (*store.Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, ok := f.jobs[id]
+	if !ok {
+		return nil, store.ErrJobNotFound
+	}
+	return &job, nil
+}
+
+func (f *fakeJobRepo) ListJobs(ctx context.Context) ([]store.Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	all := make([]store.Job, 0, len(f.jobs))
+	for _, job := range f.jobs {
+		all = append(all, job)
+	}
+	return all, nil
+}
+
+func (f *fakeJobRepo) UpdateJobPhase(ctx context.Context, id string, phase store.JobPhase, errMsg string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, ok := f.jobs[id]
+	if !ok {
+		return store.ErrJobNotFound
+	}
+	job.Phase = phase
+	job.Error = errMsg
+	f.jobs[id] = job
+	return nil
+}
+
+func (f *fakeJobRepo) SetJobResult(ctx context.Context, id string, resultJSON string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, ok := f.jobs[id]
+	if !ok {
+		return store.ErrJobNotFound
+	}
+	job.ResultJSON = resultJSON
+	f.jobs[id] = job
+	return nil
+}
+
+func (f *fakeJobRepo) AppendJobOutput(ctx context.Context, id string, stdout, stderr string) error {
+	return nil
+}
+
+func (f *fakeJobRepo) get(id string) store.Job {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jobs[id]
+}
+
+type fakeVMRepo struct{}
+
+func (fakeVMRepo) Create(ctx context.Context, vm store.VM) error { return nil }
+
+func (fakeVMRepo) Get(ctx context.Context, nodeName string) (*store.VM, error) {
+	return nil, store.ErrNotFound
+}
+
+func (fakeVMRepo) List(ctx context.Context) ([]store.VM, error) { return nil, nil }
+
+func (fakeVMRepo) Delete(ctx context.Context, nodeName string) error { return nil }
+
+func (fakeVMRepo) FindByMasterIPAndToken(ctx context.Context, masterIP, token string) (*store.VM, error) {
+	return nil, store.ErrNotFound
+}
+
+func (fakeVMRepo) UpdateStatus(ctx context.Context, nodeName string, status store.Status) error {
+	return nil
+}
+
+type fakeRunner struct{}
+
+func (fakeRunner) Create(ctx context.Context, spec provisioner.VMSpec) (provisioner.VMHandle, error) {
+	return provisioner.VMHandle{Name: spec.Name, UID: spec.UID}, nil
+}
+
+func (fakeRunner) Get(ctx context.Context, name string) (provisioner.VMStatus, error) {
+	return provisioner.VMStatus{Name: name, IP: "10.0.0.5", Running: true}, nil
+}
+
+func (fakeRunner) Delete(ctx context.Context, name string) error { return nil }
+
+// TestResumeRequeuesQueuedAndFailsInFlightJobs exercises the three
+// outcomes Resume needs to handle after a restart: a job still
+// in JobPhaseQueued is safe to retry and should complete normally, a job
+// caught mid-provisioning should be failed rather than silently resumed,
+// and a job already in a terminal phase should be left untouched.
+func TestResumeRequeuesQueuedAndFailsInFlightJobs(t *testing.T) {
+	repo := newFakeJobRepo()
+	repo.jobs["queued-job"] = store.Job{ID: "queued-job", NodeType: "worker", Phase: store.JobPhaseQueued, RequestJSON: `{"nodeName":"n1"}`}
+	repo.jobs["running-job"] = store.Job{ID: "running-job", NodeType: "worker", Phase: store.JobPhaseIgniteRun}
+	repo.jobs["done-job"] = store.Job{ID: "done-job", NodeType: "worker", Phase: store.JobPhaseComplete}
+
+	engine := provision.New(provision.WithStore(fakeVMRepo{}), provision.WithRunner(fakeRunner{}))
+	manager := NewManager(engine, repo, 1)
+
+	if err := manager.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && repo.get("queued-job").Phase != store.JobPhaseComplete {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if phase := repo.get("queued-job").Phase; phase != store.JobPhaseComplete {
+		t.Errorf("queued-job phase = %q, want complete (re-queued and processed)", phase)
+	}
+	if phase := repo.get("running-job").Phase; phase != store.JobPhaseFailed {
+		t.Errorf("running-job phase = %q, want failed (interrupted mid-provisioning)", phase)
+	}
+	if phase := repo.get("done-job").Phase; phase != store.JobPhaseComplete {
+		t.Errorf("done-job phase = %q, want unchanged complete", phase)
+	}
+}
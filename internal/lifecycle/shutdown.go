@@ -0,0 +1,59 @@
+// Package lifecycle traps termination signals so the server stops
+// accepting new work and drains in-flight provisioning before the process
+// exits, instead of dying mid-request and leaking temp files or
+// half-created VMs.
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"ignite-api/internal/logger"
+)
+
+// Trap blocks until a termination signal arrives. SIGQUIT dumps every
+// goroutine's stack (as `kill -QUIT` does for a stuck process) and keeps
+// listening rather than shutting down. On the first SIGINT/SIGTERM it runs
+// onShutdown in the background with a context cancelled after timeout, then
+// exits 0 once onShutdown returns; two further INT/TERM signals force an
+// immediate exit so an operator is never stuck waiting on a hung drain.
+func Trap(timeout time.Duration, onShutdown func(ctx context.Context)) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+
+	interrupts := 0
+	for sig := range sigCh {
+		if sig == syscall.SIGQUIT {
+			dumpStacks()
+			continue
+		}
+
+		interrupts++
+		if interrupts == 1 {
+			logger.Info("received %s, draining in-flight work (send it 2 more times to force exit)...", sig)
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				defer cancel()
+				onShutdown(ctx)
+				os.Exit(0)
+			}()
+			continue
+		}
+
+		logger.Warn("received %s again (%d/3)", sig, interrupts)
+		if interrupts >= 3 {
+			logger.Warn("forcing immediate exit")
+			os.Exit(1)
+		}
+	}
+}
+
+func dumpStacks() {
+	buf := make([]byte, 1<<20)
+	n := runtime.Stack(buf, true)
+	logger.Warn("goroutine dump:\n%s", buf[:n])
+}
@@ -1,54 +1,153 @@
+// Package logger provides a structured logger for the API server, backed by
+// log/slog. It supports configurable levels, JSON or text encoding, optional
+// rotation to a file, and per-request field propagation via context.
 package logger
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
 	"time"
-)
 
-var (
-	InfoLogger  *log.Logger
-	WarnLogger  *log.Logger
-	ErrorLogger *log.Logger
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+type ctxKey int
+
 const (
-	colorReset  = "\033[0m"
-	colorRed    = "\033[31m"
-	colorGreen  = "\033[32m"
-	colorYellow = "\033[33m"
-	colorBlue   = "\033[34m"
+	requestIDKey ctxKey = iota
+	nodeNameKey
+	vmUIDKey
 )
 
-func init() {
-	InfoLogger = log.New(os.Stdout, fmt.Sprintf("%s[INFO]%s ", colorGreen, colorReset), log.Ldate|log.Ltime)
-	WarnLogger = log.New(os.Stdout, fmt.Sprintf("%s[WARN]%s ", colorYellow, colorReset), log.Ldate|log.Ltime)
-	ErrorLogger = log.New(os.Stdout, fmt.Sprintf("%s[ERROR]%s ", colorRed, colorReset), log.Ldate|log.Ltime)
+// Config controls how the package-level logger is constructed.
+type Config struct {
+	// Level is one of debug, info, warn, error. Defaults to info.
+	Level string
+	// Format is either "json" or "text". Defaults to json.
+	Format string
+	// OutputPath, when set, rotates logs to this file instead of stdout.
+	OutputPath string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+}
+
+var base = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// Init reconfigures the package-level logger. It should be called once at
+// startup before any handlers log; it is not safe for concurrent use.
+func Init(cfg Config) {
+	base = slog.New(newHandler(cfg))
+}
+
+func newHandler(cfg Config) slog.Handler {
+	var out io.Writer = os.Stdout
+	if cfg.OutputPath != "" {
+		out = &lumberjack.Logger{
+			Filename:   cfg.OutputPath,
+			MaxSize:    orDefault(cfg.MaxSizeMB, 100),
+			MaxBackups: orDefault(cfg.MaxBackups, 5),
+			MaxAge:     orDefault(cfg.MaxAgeDays, 28),
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: parseLevel(cfg.Level)}
+	if strings.EqualFold(cfg.Format, "text") {
+		return slog.NewTextHandler(out, opts)
+	}
+	return slog.NewJSONHandler(out, opts)
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
 }
 
-// Info logs information messages
+func orDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+// Debug logs a debug-level message.
+func Debug(format string, v ...interface{}) {
+	base.Debug(fmt.Sprintf(format, v...))
+}
+
+// Info logs an info-level message.
 func Info(format string, v ...interface{}) {
-	InfoLogger.Printf(format, v...)
+	base.Info(fmt.Sprintf(format, v...))
 }
 
-// Warn logs warning messages
+// Warn logs a warn-level message.
 func Warn(format string, v ...interface{}) {
-	WarnLogger.Printf(format, v...)
+	base.Warn(fmt.Sprintf(format, v...))
 }
 
-// Error logs error messages
+// Error logs an error-level message.
 func Error(format string, v ...interface{}) {
-	ErrorLogger.Printf(format, v...)
+	base.Error(fmt.Sprintf(format, v...))
 }
 
-// Fatal logs error message and exits
+// Fatal logs an error-level message and exits the process.
 func Fatal(format string, v ...interface{}) {
-	ErrorLogger.Printf(format, v...)
+	base.Error(fmt.Sprintf(format, v...))
 	os.Exit(1)
 }
 
-// RequestLog logs HTTP request information
-func RequestLog(method, path, ip string, duration time.Duration) {
-	InfoLogger.Printf("%s[%s] %s from %s took %v", colorBlue, method, path, ip, duration)
+// RequestLog emits structured fields describing one completed HTTP request,
+// suitable for shipping to ELK/Loki without regex parsing.
+func RequestLog(requestID, method, path, ip string, status int, duration time.Duration) {
+	base.Info("http request",
+		"method", method,
+		"path", path,
+		"ip", ip,
+		"status", status,
+		"duration_ms", duration.Milliseconds(),
+		"request_id", requestID,
+	)
+}
+
+// WithNodeContext attaches a node name and VM UID to ctx so that a later
+// WithContext call includes them automatically. Handlers in internal/api
+// should call this as soon as the node/VM identity is known.
+func WithNodeContext(ctx context.Context, nodeName, vmUID string) context.Context {
+	ctx = context.WithValue(ctx, nodeNameKey, nodeName)
+	ctx = context.WithValue(ctx, vmUIDKey, vmUID)
+	return ctx
+}
+
+// WithRequestID attaches the Fiber request ID to ctx.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// WithContext returns a logger enriched with whatever request-scoped fields
+// (request ID, node name, VM UID) have been attached to ctx, so handlers and
+// internal/utils helpers don't need to thread them through manually.
+func WithContext(ctx context.Context) *slog.Logger {
+	l := base
+	if v, ok := ctx.Value(requestIDKey).(string); ok && v != "" {
+		l = l.With("request_id", v)
+	}
+	if v, ok := ctx.Value(nodeNameKey).(string); ok && v != "" {
+		l = l.With("node_name", v)
+	}
+	if v, ok := ctx.Value(vmUIDKey).(string); ok && v != "" {
+		l = l.With("vm_uid", v)
+	}
+	return l
 }
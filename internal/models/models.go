@@ -1,5 +1,7 @@
 package models
 
+import "ignite-api/internal/config"
+
 // ProvisionRequest represents the API request payload
 type ProvisionRequest struct {
 	NodeName  string `json:"nodeName"`
@@ -12,6 +14,16 @@ type ProvisionRequest struct {
 	Memory    string `json:"memory"`
 	ImageOCI  string `json:"imageOci"`
 	EnableSSH bool   `json:"enableSsh"`
+
+	// Backend selects the VM backend ("ignite", "qemu", "docker",
+	// "firecracker") this node should be provisioned on, overriding the
+	// server's configured default. Empty means use the default.
+	Backend string `json:"backend,omitempty"`
+
+	// Ignition carries optional first-boot customization (SSH keys, extra
+	// systemd units, extra files) delivered to the node alongside its
+	// identity file. Nil means no overrides.
+	Ignition *config.IgnitionOverrides `json:"ignition,omitempty"`
 }
 
 // ProvisionResponse represents the API response
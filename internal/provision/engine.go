@@ -0,0 +1,248 @@
+// Package provision holds the VM-provisioning logic independent of any
+// transport. Fiber handlers, internal/jobs' async worker pool, and a
+// future CLI or gRPC entry point can all drive the same Engine instead of
+// each re-implementing spec-building, validation, and rollback.
+package provision
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ignite-api/internal/models"
+	"ignite-api/internal/provisioner"
+	"ignite-api/internal/store"
+)
+
+// Runner is the subset of provisioner.Provisioner the engine needs to boot
+// and inspect a VM. Accepting this narrower interface, rather than
+// provisioner.Provisioner directly, makes it trivial to swap in a test
+// double that never shells out.
+type Runner interface {
+	Create(ctx context.Context, spec provisioner.VMSpec) (provisioner.VMHandle, error)
+	Get(ctx context.Context, name string) (provisioner.VMStatus, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// ImageDefaults fills in zero-valued sizing/image fields on a request that
+// didn't specify them.
+type ImageDefaults struct {
+	CPUs     int
+	DiskSize string
+	Memory   string
+	ImageOCI string
+}
+
+// Validator checks a request before it is provisioned, e.g. confirming a
+// worker's masterIP/token pair against an existing record. See
+// DefaultValidator for the check the original handler ran inline.
+type Validator func(ctx context.Context, request *models.ProvisionRequest, nodeType string) error
+
+// NodeIDFunc generates a node UID when a request doesn't supply one.
+type NodeIDFunc func() string
+
+// BackendResolver looks up the Runner registered under name, for requests
+// that ask for a specific VM backend instead of the server's configured
+// default.
+type BackendResolver func(name string) (Runner, error)
+
+// Engine is constructed via New and the With* options below; store and
+// runner are the only options callers must supply.
+type Engine struct {
+	store    store.VMRepository
+	runner   Runner
+	backends BackendResolver
+	defaults ImageDefaults
+	validate Validator
+	nodeID   NodeIDFunc
+}
+
+// Option configures an Engine constructed via New.
+type Option func(*Engine)
+
+// WithStore sets the repository provisioned VMs are persisted to.
+func WithStore(s store.VMRepository) Option {
+	return func(e *Engine) { e.store = s }
+}
+
+// WithRunner sets the VM backend used to create and inspect VMs.
+func WithRunner(r Runner) Option {
+	return func(e *Engine) { e.runner = r }
+}
+
+// WithImageDefaults sets the sizing/image fields applied when a request
+// leaves them zero-valued.
+func WithImageDefaults(d ImageDefaults) Option {
+	return func(e *Engine) { e.defaults = d }
+}
+
+// WithValidator sets the pre-provisioning check run before a request is
+// handed to the runner. Omit it to skip validation entirely.
+func WithValidator(v Validator) Option {
+	return func(e *Engine) { e.validate = v }
+}
+
+// WithNodeID sets the generator used to assign a node UID when a request
+// doesn't supply one.
+func WithNodeID(f NodeIDFunc) Option {
+	return func(e *Engine) { e.nodeID = f }
+}
+
+// WithBackendResolver overrides how a request's Backend field, when set, is
+// resolved to a Runner. It defaults to provisioner.New, so most callers
+// only need this to plug in a test double.
+func WithBackendResolver(r BackendResolver) Option {
+	return func(e *Engine) { e.backends = r }
+}
+
+// New builds an Engine from opts, defaulting ImageDefaults to the values
+// the original handlers hard-coded and BackendResolver to provisioner.New.
+func New(opts ...Option) *Engine {
+	e := &Engine{
+		defaults: ImageDefaults{CPUs: 2, DiskSize: "3GB", Memory: "1GB", ImageOCI: "shajalahamedcse/only-k3-go:v1.0.10"},
+		nodeID:   func() string { return "" },
+		backends: func(name string) (Runner, error) { return provisioner.New(name) },
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// resolveRunner returns the Runner named by request.Backend, falling back
+// to the engine's default Runner when the request didn't specify one.
+func (e *Engine) resolveRunner(request *models.ProvisionRequest) (Runner, error) {
+	if request.Backend == "" {
+		return e.runner, nil
+	}
+	return e.backends(request.Backend)
+}
+
+// Validate runs the configured Validator, if any.
+func (e *Engine) Validate(ctx context.Context, request *models.ProvisionRequest, nodeType string) error {
+	if e.validate == nil {
+		return nil
+	}
+	return e.validate(ctx, request, nodeType)
+}
+
+// BuildSpec maps request onto a backend-agnostic VMSpec, applying
+// ImageDefaults and writing the node's identity (plus any request.Ignition
+// overrides) to temp files included in CopyFiles. It also resolves the
+// Runner named by request.Backend, falling back to the engine's default
+// backend when unset. The returned cleanup func removes the temp files and
+// must be called once the VM has been created.
+func (e *Engine) BuildSpec(request *models.ProvisionRequest) (spec provisioner.VMSpec, runner Runner, cleanup func(), err error) {
+	if request.NodeUID == "" {
+		request.NodeUID = e.nodeID()
+	}
+
+	runner, err = e.resolveRunner(request)
+	if err != nil {
+		return provisioner.VMSpec{}, nil, nil, err
+	}
+
+	spec = provisioner.VMSpec{
+		Name:      request.NodeName,
+		UID:       request.NodeUID,
+		CPUs:      intOrDefault(request.CPUs, e.defaults.CPUs),
+		DiskSize:  strOrDefault(request.DiskSize, e.defaults.DiskSize),
+		Memory:    strOrDefault(request.Memory, e.defaults.Memory),
+		ImageOCI:  strOrDefault(request.ImageOCI, e.defaults.ImageOCI),
+		EnableSSH: request.EnableSSH,
+	}
+
+	nodeFiles, cleanup, err := buildNodeFiles(request)
+	if err != nil {
+		return provisioner.VMSpec{}, nil, nil, err
+	}
+	spec.CopyFiles = append(spec.CopyFiles, nodeFiles...)
+
+	return spec, runner, cleanup, nil
+}
+
+// Create boots spec through runner, the Runner BuildSpec resolved for this
+// request.
+func (e *Engine) Create(ctx context.Context, runner Runner, spec provisioner.VMSpec) (provisioner.VMHandle, error) {
+	return runner.Create(ctx, spec)
+}
+
+// Status returns the current status of the named VM, through the same
+// runner BuildSpec resolved for it.
+func (e *Engine) Status(ctx context.Context, runner Runner, name string) (provisioner.VMStatus, error) {
+	return runner.Get(ctx, name)
+}
+
+// Persist stores the provisioning result for request. If storage fails, it
+// rolls back the VM runner just created rather than leaving an orphaned VM
+// with no record.
+func (e *Engine) Persist(ctx context.Context, runner Runner, request *models.ProvisionRequest, spec provisioner.VMSpec, status provisioner.VMStatus) error {
+	now := time.Now().UTC()
+	if err := e.store.Create(ctx, store.VM{
+		NodeName:  request.NodeName,
+		NodeUID:   request.NodeUID,
+		MasterIP:  status.IP,
+		NodeType:  request.NodeType,
+		Token:     request.Token,
+		ImageOCI:  spec.ImageOCI,
+		Backend:   request.Backend,
+		Status:    store.StatusReady,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}); err != nil {
+		if delErr := runner.Delete(ctx, request.NodeName); delErr != nil {
+			return fmt.Errorf("failed to store provision info (and failed to roll back vm: %v): %w", delErr, err)
+		}
+		return fmt.Errorf("failed to store provision info: %w", err)
+	}
+	return nil
+}
+
+// Provision runs the full Validate -> BuildSpec -> Create -> Status ->
+// Persist pipeline in one call, for callers (a future CLI, tests) that
+// don't need the per-phase visibility internal/jobs' SSE stream wants.
+func (e *Engine) Provision(ctx context.Context, nodeType string, request *models.ProvisionRequest) (*models.ProvisionResponse, error) {
+	if err := e.Validate(ctx, request, nodeType); err != nil {
+		return nil, err
+	}
+
+	spec, runner, cleanup, err := e.BuildSpec(request)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	if _, err := e.Create(ctx, runner, spec); err != nil {
+		return nil, err
+	}
+
+	status, err := e.Status(ctx, runner, request.NodeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get master IP: %w", err)
+	}
+
+	if err := e.Persist(ctx, runner, request, spec, status); err != nil {
+		return nil, err
+	}
+
+	return &models.ProvisionResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("VM '%s' successfully provisioned", request.NodeName),
+		NodeID:   request.NodeUID,
+		MasterIP: status.IP,
+	}, nil
+}
+
+func intOrDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func strOrDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,105 @@
+package provision
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"ignite-api/internal/config"
+	"ignite-api/internal/models"
+	"ignite-api/internal/provisioner"
+	"ignite-api/internal/utils"
+)
+
+// buildNodeFiles renders every file this node's guest needs copied in at
+// boot. None of the backends (ignite/qemu/docker/firecracker) run an
+// Ignition interpreter — they all just copy host files to guest paths via
+// VMSpec.CopyFiles — so the node identity is written as the same flat
+// {name,uid,nodeType,token,masterIP} JSON at /root/config.json boot
+// scripts have always read, rather than wrapped in an Ignition document.
+// request.Ignition overrides (extra files, systemd units, SSH keys) are
+// honored the same way: rendered to their own temp files and copied to the
+// paths they'd occupy on a real Ignition-provisioned guest. The returned
+// cleanup removes every temp file created and must be called once the VM
+// has been created.
+func buildNodeFiles(request *models.ProvisionRequest) (files []provisioner.FileMapping, cleanup func(), err error) {
+	var tempFiles []string
+	cleanup = func() {
+		for _, f := range tempFiles {
+			os.Remove(f)
+		}
+	}
+	addFile := func(content []byte, pattern, vmPath string) error {
+		hostPath, err := utils.WriteTempFile(content, pattern)
+		if err != nil {
+			return err
+		}
+		tempFiles = append(tempFiles, hostPath)
+		files = append(files, provisioner.FileMapping{HostPath: hostPath, VMPath: vmPath})
+		return nil
+	}
+
+	identity := config.NodeIdentity{
+		Name:     request.NodeName,
+		UID:      request.NodeUID,
+		NodeType: request.NodeType,
+		Token:    request.Token,
+		MasterIP: request.MasterIP,
+	}
+	identityJSON, err := json.Marshal(identity)
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("failed to marshal node identity: %w", err)
+	}
+	if err := addFile(identityJSON, "config-*.json", "/root/config.json"); err != nil {
+		return nil, cleanup, err
+	}
+
+	if request.Ignition == nil {
+		return files, cleanup, nil
+	}
+
+	if len(request.Ignition.SSHKeys) > 0 {
+		authorizedKeys := strings.Join(request.Ignition.SSHKeys, "\n") + "\n"
+		if err := addFile([]byte(authorizedKeys), "authorized_keys-*", "/root/.ssh/authorized_keys"); err != nil {
+			return nil, cleanup, err
+		}
+	}
+
+	for _, f := range request.Ignition.ExtraFiles {
+		content, err := decodeFileContents(f.Contents)
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("failed to decode extra file %s: %w", f.Path, err)
+		}
+		if err := addFile(content, "extra-file-*", f.Path); err != nil {
+			return nil, cleanup, err
+		}
+	}
+
+	for _, unit := range request.Ignition.ExtraUnits {
+		if unit.Contents != "" {
+			if err := addFile([]byte(unit.Contents), "unit-*", "/etc/systemd/system/"+unit.Name); err != nil {
+				return nil, cleanup, err
+			}
+		}
+		for _, dropin := range unit.Dropins {
+			vmPath := fmt.Sprintf("/etc/systemd/system/%s.d/%s", unit.Name, dropin.Name)
+			if err := addFile([]byte(dropin.Contents), "dropin-*", vmPath); err != nil {
+				return nil, cleanup, err
+			}
+		}
+	}
+
+	return files, cleanup, nil
+}
+
+// decodeFileContents decodes a config.FileContents "data:;base64,<...>"
+// source back to raw bytes.
+func decodeFileContents(contents config.FileContents) ([]byte, error) {
+	_, encoded, ok := strings.Cut(contents.Source, "base64,")
+	if !ok {
+		return nil, fmt.Errorf("unsupported file contents source %q", contents.Source)
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
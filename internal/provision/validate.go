@@ -0,0 +1,30 @@
+package provision
+
+import (
+	"context"
+	"fmt"
+
+	"ignite-api/internal/models"
+	"ignite-api/internal/store"
+)
+
+// DefaultValidator returns a Validator enforcing the same checks the
+// original handler ran inline: NodeName/NodeUID are required, and worker
+// requests must present a masterIP/token pair matching an existing VM
+// record (of any node type).
+func DefaultValidator(vms store.VMRepository) Validator {
+	return func(ctx context.Context, request *models.ProvisionRequest, nodeType string) error {
+		if request.NodeName == "" || request.NodeUID == "" {
+			return fmt.Errorf("NodeName and NodeUID are required fields")
+		}
+		if nodeType == "worker" && (request.MasterIP == "" || request.NodeType != "worker") {
+			return fmt.Errorf("NodeName, NodeUID, MasterIP, and NodeType 'worker' are required fields")
+		}
+		if nodeType == "worker" {
+			if _, err := vms.FindByMasterIPAndToken(ctx, request.MasterIP, request.Token); err != nil {
+				return fmt.Errorf("Token and MasterIP do not match any existing records")
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package provisioner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"ignite-api/internal/logger"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// dockerProvisioner runs "VMs" as plain containers. It exists so the API
+// server and its tests can run in CI or on a dev laptop without KVM.
+type dockerProvisioner struct{}
+
+func newDockerProvisioner() Provisioner {
+	return dockerProvisioner{}
+}
+
+func (dockerProvisioner) Create(ctx context.Context, spec VMSpec) (VMHandle, error) {
+	args := []string{"run", "-d", "--name", spec.Name, "--cpus", fmt.Sprintf("%d", spec.CPUs), "--memory", spec.Memory}
+	for _, f := range spec.CopyFiles {
+		args = append(args, "-v", fmt.Sprintf("%s:%s:ro", f.HostPath, f.VMPath))
+	}
+	args = append(args, spec.ImageOCI)
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return VMHandle{}, classifyError(err, stderr.String())
+	}
+
+	logger.Info("Provisioning VM via docker backend: %s", spec.Name)
+	return VMHandle{Name: spec.Name, UID: spec.UID}, nil
+}
+
+func (dockerProvisioner) Delete(ctx context.Context, name string) error {
+	cmd := exec.CommandContext(ctx, "docker", "rm", "-f", name)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return classifyError(err, stderr.String())
+	}
+	return nil
+}
+
+func (dockerProvisioner) Get(ctx context.Context, name string) (VMStatus, error) {
+	cmd := exec.CommandContext(ctx, "docker", "inspect",
+		"--format", "{{.Id}}\t{{.Config.Image}}\t{{.State.Running}}\t{{.State.StartedAt}}\t{{.NetworkSettings.IPAddress}}", name)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return VMStatus{}, classifyError(err, stderr.String())
+	}
+
+	fields := strings.Split(strings.TrimSpace(stdout.String()), "\t")
+	if len(fields) != 5 {
+		return VMStatus{}, fmt.Errorf("unexpected docker inspect output: %q", stdout.String())
+	}
+	return VMStatus{
+		Name:    name,
+		UID:     fields[0],
+		Image:   fields[1],
+		Running: fields[2] == "true",
+		Started: fields[3],
+		IP:      fields[4],
+	}, nil
+}
+
+func (dockerProvisioner) List(ctx context.Context) ([]VMStatus, error) {
+	cmd := exec.CommandContext(ctx, "docker", "ps", "-a", "--format", "{{.Names}}")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, classifyError(err, stderr.String())
+	}
+
+	p := dockerProvisioner{}
+	var vms []VMStatus
+	for _, name := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		if name == "" {
+			continue
+		}
+		vm, err := p.Get(ctx, name)
+		if err != nil {
+			continue
+		}
+		vms = append(vms, vm)
+	}
+	return vms, nil
+}
+
+func (dockerProvisioner) Exec(ctx context.Context, name string, cmdArgs []string) (io.ReadCloser, error) {
+	args := append([]string{"exec", name}, cmdArgs...)
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, classifyError(err, stderr.String())
+	}
+	return io.NopCloser(bytes.NewReader(stdout.Bytes())), nil
+}
@@ -0,0 +1,40 @@
+package provisioner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Typed backend failures, classified from CLI stderr so handlers can return
+// meaningful HTTP status codes instead of forwarding raw output.
+var (
+	ErrVMNotFound   = errors.New("vm not found")
+	ErrImageMissing = errors.New("image missing")
+	ErrKernelPull   = errors.New("kernel pull failed")
+)
+
+// classifyError maps a failed backend CLI invocation's stderr onto one of
+// the typed errors above, falling back to a generic wrapped error when the
+// output doesn't match a known failure mode.
+func classifyError(genericErr error, stderr string) error {
+	switch {
+	case containsAny(stderr, "no such vm", "vm not found", "does not exist"):
+		return fmt.Errorf("%w: %s", ErrVMNotFound, stderr)
+	case containsAny(stderr, "no such image", "image missing", "failed to resolve image"):
+		return fmt.Errorf("%w: %s", ErrImageMissing, stderr)
+	case containsAny(stderr, "failed to pull kernel", "kernel image"):
+		return fmt.Errorf("%w: %s", ErrKernelPull, stderr)
+	default:
+		return fmt.Errorf("backend command failed: %w: %s", genericErr, stderr)
+	}
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(strings.ToLower(s), sub) {
+			return true
+		}
+	}
+	return false
+}
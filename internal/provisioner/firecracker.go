@@ -0,0 +1,269 @@
+package provisioner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"ignite-api/internal/logger"
+	"ignite-api/internal/utils"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// firecrackerSubnet is the private /24 static IPs are assigned from. There
+// is no DHCP or firecracker-API-driven address assignment here, so every VM
+// is handed a deterministic address (derived from its name) on its own
+// host-side tap device (see tapDeviceName), and that address is baked into the kernel's
+// boot args via the "ip=" parameter.
+const (
+	firecrackerSubnet  = "169.254.100"
+	firecrackerHostIP  = firecrackerSubnet + ".1"
+	firecrackerNetmask = "255.255.255.0"
+)
+
+// firecrackerRunDir holds the per-VM API socket and pidfile firecracker
+// instances are tracked by, since the bare firecracker binary (unlike
+// ignite, which wraps it) exposes no "list running VMs" command of its own.
+const firecrackerRunDir = "/run/firecracker-vms"
+
+// firecrackerMachineConfig is the subset of firecracker's --config-file
+// schema this backend fills in: boot source, a single rootfs drive, a single
+// tap network interface, and machine sizing. See https://github.com/firecracker-microvm/firecracker/blob/main/docs/api_requests/actions.md.
+type firecrackerMachineConfig struct {
+	BootSource struct {
+		KernelImagePath string `json:"kernel_image_path"`
+		BootArgs        string `json:"boot_args"`
+	} `json:"boot-source"`
+	Drives []struct {
+		DriveID      string `json:"drive_id"`
+		PathOnHost   string `json:"path_on_host"`
+		IsRootDevice bool   `json:"is_root_device"`
+		IsReadOnly   bool   `json:"is_read_only"`
+	} `json:"drives"`
+	NetworkInterfaces []struct {
+		IfaceID     string `json:"iface_id"`
+		HostDevName string `json:"host_dev_name"`
+	} `json:"network-interfaces"`
+	MachineConfig struct {
+		VCPUCount  int  `json:"vcpu_count"`
+		MemSizeMib int  `json:"mem_size_mib"`
+		SMT        bool `json:"smt"`
+	} `json:"machine-config"`
+}
+
+// firecrackerProvisioner runs VMs as bare firecracker microVM processes,
+// bypassing ignite's containerd-backed image management entirely. Its
+// ImageOCI field is interpreted as a local path to a prebuilt rootfs image
+// rather than an OCI reference, since the raw firecracker API has no image
+// puller of its own.
+type firecrackerProvisioner struct{}
+
+func newFirecrackerProvisioner() Provisioner {
+	return firecrackerProvisioner{}
+}
+
+func (firecrackerProvisioner) Create(ctx context.Context, spec VMSpec) (VMHandle, error) {
+	if err := os.MkdirAll(firecrackerRunDir, 0755); err != nil {
+		return VMHandle{}, fmt.Errorf("failed to create firecracker run dir: %w", err)
+	}
+
+	tapDevice := tapDeviceName(spec.Name)
+	if err := exec.CommandContext(ctx, "ip", "tuntap", "add", "dev", tapDevice, "mode", "tap").Run(); err != nil {
+		return VMHandle{}, fmt.Errorf("failed to create tap device %s: %w", tapDevice, err)
+	}
+	if err := exec.CommandContext(ctx, "ip", "addr", "add", firecrackerHostIP+"/24", "dev", tapDevice).Run(); err != nil {
+		return VMHandle{}, fmt.Errorf("failed to assign address to tap device %s: %w", tapDevice, err)
+	}
+	if err := exec.CommandContext(ctx, "ip", "link", "set", tapDevice, "up").Run(); err != nil {
+		return VMHandle{}, fmt.Errorf("failed to bring up tap device %s: %w", tapDevice, err)
+	}
+
+	guestIP := guestIP(spec.Name)
+
+	var cfg firecrackerMachineConfig
+	cfg.BootSource.KernelImagePath = "/var/lib/firecracker/vmlinux"
+	cfg.BootSource.BootArgs = fmt.Sprintf("console=ttyS0 reboot=k panic=1 pci=off ip=%s::%s:%s::eth0:off",
+		guestIP, firecrackerHostIP, firecrackerNetmask)
+	cfg.Drives = append(cfg.Drives, struct {
+		DriveID      string `json:"drive_id"`
+		PathOnHost   string `json:"path_on_host"`
+		IsRootDevice bool   `json:"is_root_device"`
+		IsReadOnly   bool   `json:"is_read_only"`
+	}{DriveID: "rootfs", PathOnHost: spec.ImageOCI, IsRootDevice: true, IsReadOnly: false})
+	cfg.NetworkInterfaces = append(cfg.NetworkInterfaces, struct {
+		IfaceID     string `json:"iface_id"`
+		HostDevName string `json:"host_dev_name"`
+	}{IfaceID: "eth0", HostDevName: tapDevice})
+	cfg.MachineConfig.VCPUCount = spec.CPUs
+	cfg.MachineConfig.MemSizeMib = parseMemMib(spec.Memory)
+
+	configJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return VMHandle{}, fmt.Errorf("failed to marshal firecracker config: %w", err)
+	}
+	configFileName, err := utils.WriteTempFile(configJSON, fmt.Sprintf("firecracker-%s-*.json", spec.Name))
+	if err != nil {
+		return VMHandle{}, err
+	}
+
+	sockPath := filepath.Join(firecrackerRunDir, spec.Name+".sock")
+	os.Remove(sockPath)
+
+	cmd := exec.CommandContext(ctx, "firecracker", "--api-sock", sockPath, "--config-file", configFileName)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return VMHandle{}, classifyError(err, stderr.String())
+	}
+
+	pidFile := filepath.Join(firecrackerRunDir, spec.Name+".pid")
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(cmd.Process.Pid)), 0644); err != nil {
+		return VMHandle{}, fmt.Errorf("failed to record firecracker pid: %w", err)
+	}
+	ipFile := filepath.Join(firecrackerRunDir, spec.Name+".ip")
+	if err := os.WriteFile(ipFile, []byte(guestIP), 0644); err != nil {
+		return VMHandle{}, fmt.Errorf("failed to record firecracker guest ip: %w", err)
+	}
+
+	logger.Info("Provisioning VM via firecracker backend: %s", spec.Name)
+	return VMHandle{Name: spec.Name, UID: spec.UID}, nil
+}
+
+func (firecrackerProvisioner) Delete(ctx context.Context, name string) error {
+	pid, err := firecrackerPID(name)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "kill", strconv.Itoa(pid))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return classifyError(err, stderr.String())
+	}
+
+	os.Remove(filepath.Join(firecrackerRunDir, name+".pid"))
+	os.Remove(filepath.Join(firecrackerRunDir, name+".sock"))
+	os.Remove(filepath.Join(firecrackerRunDir, name+".ip"))
+	_ = exec.CommandContext(ctx, "ip", "link", "del", tapDeviceName(name)).Run()
+	return nil
+}
+
+func (firecrackerProvisioner) Get(ctx context.Context, name string) (VMStatus, error) {
+	vms, err := (firecrackerProvisioner{}).List(ctx)
+	if err != nil {
+		return VMStatus{}, err
+	}
+	for _, vm := range vms {
+		if vm.Name == name {
+			return vm, nil
+		}
+	}
+	return VMStatus{}, fmt.Errorf("%w: %q", ErrVMNotFound, name)
+}
+
+func (firecrackerProvisioner) List(ctx context.Context) ([]VMStatus, error) {
+	entries, err := os.ReadDir(firecrackerRunDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read firecracker run dir: %w", err)
+	}
+
+	var vms []VMStatus
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".pid") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".pid")
+		pid, err := firecrackerPID(name)
+		if err != nil {
+			continue
+		}
+		vms = append(vms, VMStatus{Name: name, Running: processAlive(pid), IP: readGuestIP(name)})
+	}
+	return vms, nil
+}
+
+func (firecrackerProvisioner) Exec(ctx context.Context, name string, cmdArgs []string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("firecracker backend does not support Exec without a guest agent")
+}
+
+func firecrackerPID(name string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(firecrackerRunDir, name+".pid"))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrVMNotFound, name)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("corrupt pidfile for %q: %w", name, err)
+	}
+	return pid, nil
+}
+
+// processAlive reports whether pid is still running by probing it with
+// signal 0, which the kernel delivers to nothing but still validates the
+// pid exists and is ours to signal.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// tapDeviceName returns the host-side tap device a VM's network interface
+// is attached to. Linux interface names are capped at 15 bytes, so this is
+// a fixed-width hash rather than the VM name itself.
+func tapDeviceName(name string) string {
+	return fmt.Sprintf("fc-%08x", hashName(name))
+}
+
+// guestIP deterministically derives a static address on firecrackerSubnet
+// for name, so the same VM always gets the same address across restarts
+// without needing DHCP or the firecracker API's own network setup.
+func guestIP(name string) string {
+	return fmt.Sprintf("%s.%d", firecrackerSubnet, 2+hashName(name)%250)
+}
+
+func hashName(name string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return h.Sum32()
+}
+
+// readGuestIP returns the address guestIP assigned to name at Create time,
+// or "" if its sidecar file is missing (e.g. the VM predates this field).
+func readGuestIP(name string) string {
+	data, err := os.ReadFile(filepath.Join(firecrackerRunDir, name+".ip"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// parseMemMib converts a VMSpec.Memory string like "1GB" or "512MB" into
+// the MiB integer firecracker's machine-config expects, defaulting to
+// 1024 MiB if it can't be parsed.
+func parseMemMib(memory string) int {
+	memory = strings.ToUpper(strings.TrimSpace(memory))
+	switch {
+	case strings.HasSuffix(memory, "GB"):
+		if v, err := strconv.Atoi(strings.TrimSuffix(memory, "GB")); err == nil {
+			return v * 1024
+		}
+	case strings.HasSuffix(memory, "MB"):
+		if v, err := strconv.Atoi(strings.TrimSuffix(memory, "MB")); err == nil {
+			return v
+		}
+	}
+	return 1024
+}
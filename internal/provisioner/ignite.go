@@ -0,0 +1,179 @@
+package provisioner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"ignite-api/internal/logger"
+	"ignite-api/internal/utils"
+	"io"
+	"os"
+	"os/exec"
+
+	"gopkg.in/yaml.v2"
+)
+
+// igniteManifest is the weaveworks/ignite VM manifest format. It is private
+// to this backend; other backends own their own artifact shapes.
+type igniteManifest struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+		UID  string `yaml:"uid"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Image     map[string]string `yaml:"image"`
+		CPUs      int               `yaml:"cpus"`
+		DiskSize  string            `yaml:"diskSize"`
+		Memory    string            `yaml:"memory"`
+		CopyFiles []struct {
+			HostPath string `yaml:"hostPath"`
+			VMPath   string `yaml:"vmPath"`
+		} `yaml:"copyFiles"`
+		SSH bool `yaml:"ssh"`
+	} `yaml:"spec"`
+}
+
+// ignitePSEntry mirrors the shape of one element in `ignite ps -o json`'s
+// output array, which is close to ignite's own VM API type.
+type ignitePSEntry struct {
+	Metadata struct {
+		Name string `json:"name"`
+		UID  string `json:"uid"`
+	} `json:"metadata"`
+	Spec struct {
+		Image struct {
+			OCI string `json:"oci"`
+		} `json:"image"`
+	} `json:"spec"`
+	Status struct {
+		Running     bool     `json:"running"`
+		StartTime   string   `json:"startTime"`
+		IPAddresses []string `json:"ipAddresses"`
+	} `json:"status"`
+}
+
+// igniteProvisioner is the default Provisioner, shelling out to the ignite
+// CLI exactly as the original handlers did.
+type igniteProvisioner struct{}
+
+func newIgniteProvisioner() Provisioner {
+	return igniteProvisioner{}
+}
+
+func (igniteProvisioner) Create(ctx context.Context, spec VMSpec) (VMHandle, error) {
+	manifest := igniteManifest{APIVersion: "ignite.weave.works/v1alpha4", Kind: "VM"}
+	manifest.Metadata.Name = spec.Name
+	manifest.Metadata.UID = spec.UID
+	manifest.Spec.Image = map[string]string{"oci": spec.ImageOCI}
+	manifest.Spec.CPUs = spec.CPUs
+	manifest.Spec.DiskSize = spec.DiskSize
+	manifest.Spec.Memory = spec.Memory
+	manifest.Spec.SSH = spec.EnableSSH
+	for _, f := range spec.CopyFiles {
+		manifest.Spec.CopyFiles = append(manifest.Spec.CopyFiles, struct {
+			HostPath string `yaml:"hostPath"`
+			VMPath   string `yaml:"vmPath"`
+		}{HostPath: f.HostPath, VMPath: f.VMPath})
+	}
+
+	manifestYAML, err := yaml.Marshal(manifest)
+	if err != nil {
+		return VMHandle{}, fmt.Errorf("error marshaling ignite manifest: %w", err)
+	}
+	manifestFileName, err := utils.WriteTempFile(manifestYAML, "ignite-config-*.yaml")
+	if err != nil {
+		return VMHandle{}, err
+	}
+	defer os.Remove(manifestFileName)
+
+	cmd := exec.CommandContext(ctx, "sudo", "ignite", "run", "--config", manifestFileName)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	logger.Info("Provisioning VM: %s", spec.Name)
+	if err := cmd.Run(); err != nil {
+		logger.Error("Failed to run ignite: %v\nStdout: %s\nStderr: %s", err, stdout.String(), stderr.String())
+		return VMHandle{}, classifyError(err, stderr.String())
+	}
+
+	return VMHandle{Name: spec.Name, UID: spec.UID}, nil
+}
+
+func (igniteProvisioner) Delete(ctx context.Context, name string) error {
+	if err := runIgniteVMCommand(ctx, "stop", name); err != nil {
+		return err
+	}
+	return runIgniteVMCommand(ctx, "rm", name)
+}
+
+func (p igniteProvisioner) Get(ctx context.Context, name string) (VMStatus, error) {
+	vms, err := p.List(ctx)
+	if err != nil {
+		return VMStatus{}, err
+	}
+	for _, vm := range vms {
+		if vm.Name == name {
+			return vm, nil
+		}
+	}
+	return VMStatus{}, fmt.Errorf("%w: %q", ErrVMNotFound, name)
+}
+
+func (igniteProvisioner) List(ctx context.Context) ([]VMStatus, error) {
+	cmd := exec.CommandContext(ctx, "sudo", "ignite", "ps", "-o", "json")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, classifyError(err, stderr.String())
+	}
+
+	var entries []ignitePSEntry
+	if err := json.Unmarshal(stdout.Bytes(), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse ignite ps json output: %w", err)
+	}
+
+	vms := make([]VMStatus, 0, len(entries))
+	for _, e := range entries {
+		ip := ""
+		if len(e.Status.IPAddresses) > 0 {
+			ip = e.Status.IPAddresses[0]
+		}
+		vms = append(vms, VMStatus{
+			Name:    e.Metadata.Name,
+			UID:     e.Metadata.UID,
+			IP:      ip,
+			Image:   e.Spec.Image.OCI,
+			Running: e.Status.Running,
+			Started: e.Status.StartTime,
+		})
+	}
+	return vms, nil
+}
+
+func (igniteProvisioner) Exec(ctx context.Context, name string, cmdArgs []string) (io.ReadCloser, error) {
+	args := append([]string{"ignite", "exec", name, "--"}, cmdArgs...)
+	cmd := exec.CommandContext(ctx, "sudo", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, classifyError(err, stderr.String())
+	}
+	return io.NopCloser(bytes.NewReader(stdout.Bytes())), nil
+}
+
+func runIgniteVMCommand(ctx context.Context, action, vmName string) error {
+	cmd := exec.CommandContext(ctx, "sudo", "ignite", "vm", action, vmName)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return classifyError(err, stderr.String())
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Package provisioner defines the VM backend port used by the rest of the
+// server. Handlers and the job manager depend only on the Provisioner
+// interface; concrete backends (ignite, qemu, docker, firecracker) each own their own
+// artifact format and exec calls.
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// FileMapping copies a file from the host into the guest at boot.
+type FileMapping struct {
+	HostPath string
+	VMPath   string
+}
+
+// VMSpec is the backend-agnostic description of a VM to create.
+type VMSpec struct {
+	Name      string
+	UID       string
+	CPUs      int
+	DiskSize  string
+	Memory    string
+	ImageOCI  string
+	EnableSSH bool
+	CopyFiles []FileMapping
+}
+
+// VMHandle identifies a VM a backend has accepted for creation.
+type VMHandle struct {
+	Name string
+	UID  string
+}
+
+// VMStatus is the backend-agnostic view of a running (or stopped) VM.
+type VMStatus struct {
+	Name    string
+	UID     string
+	IP      string
+	Image   string
+	Running bool
+	Started string
+}
+
+// Provisioner is the port every VM backend implements.
+type Provisioner interface {
+	Create(ctx context.Context, spec VMSpec) (VMHandle, error)
+	Delete(ctx context.Context, name string) error
+	Get(ctx context.Context, name string) (VMStatus, error)
+	List(ctx context.Context) ([]VMStatus, error)
+	Exec(ctx context.Context, name string, cmd []string) (io.ReadCloser, error)
+}
+
+// New constructs the Provisioner named by backend, defaulting to "ignite"
+// when backend is empty.
+func New(backend string) (Provisioner, error) {
+	switch backend {
+	case "", "ignite":
+		return newIgniteProvisioner(), nil
+	case "qemu":
+		return newQEMUProvisioner(), nil
+	case "docker":
+		return newDockerProvisioner(), nil
+	case "firecracker":
+		return newFirecrackerProvisioner(), nil
+	default:
+		return nil, fmt.Errorf("provisioner: unknown backend %q", backend)
+	}
+}
@@ -0,0 +1,149 @@
+package provisioner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"ignite-api/internal/logger"
+	"ignite-api/internal/utils"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// qemuProvisioner runs VMs as plain QEMU processes with a cloud-init seed
+// drive, for environments without ignite/KVM's firecracker dependency.
+// Its own config artifact is a cloud-init ISO rather than an ignite
+// manifest: a temp directory holding meta-data/user-data is packed with
+// genisoimage and attached as the seed drive QEMU's cloud-init datasource
+// expects. Like the firecracker backend, ImageOCI is interpreted as a
+// local path to a prebuilt qcow2 rootfs rather than an OCI reference, and
+// is attached as the VM's root drive alongside the cloud-init seed.
+type qemuProvisioner struct{}
+
+func newQEMUProvisioner() Provisioner {
+	return qemuProvisioner{}
+}
+
+func (qemuProvisioner) Create(ctx context.Context, spec VMSpec) (VMHandle, error) {
+	seedDir, err := os.MkdirTemp("", "cloud-init-*")
+	if err != nil {
+		return VMHandle{}, fmt.Errorf("failed to create cloud-init staging dir: %w", err)
+	}
+	defer os.RemoveAll(seedDir)
+
+	if err := os.WriteFile(filepath.Join(seedDir, "meta-data"), []byte(fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", spec.UID, spec.Name)), 0644); err != nil {
+		return VMHandle{}, fmt.Errorf("failed to write cloud-init meta-data: %w", err)
+	}
+
+	var userData bytes.Buffer
+	userData.WriteString("#cloud-config\n")
+	if len(spec.CopyFiles) > 0 {
+		userData.WriteString("write_files:\n")
+	}
+	for _, f := range spec.CopyFiles {
+		contents, err := os.ReadFile(f.HostPath)
+		if err != nil {
+			return VMHandle{}, fmt.Errorf("failed to read %s for cloud-init write_files: %w", f.HostPath, err)
+		}
+		fmt.Fprintf(&userData, "  - path: %s\n    content: |\n", f.VMPath)
+		for _, line := range bytes.Split(contents, []byte("\n")) {
+			fmt.Fprintf(&userData, "      %s\n", line)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(seedDir, "user-data"), userData.Bytes(), 0644); err != nil {
+		return VMHandle{}, fmt.Errorf("failed to write cloud-init user-data: %w", err)
+	}
+
+	seedISO, err := utils.WriteTempFile(nil, fmt.Sprintf("%s-seed-*.iso", spec.Name))
+	if err != nil {
+		return VMHandle{}, err
+	}
+
+	cmd := exec.CommandContext(ctx, "genisoimage", "-output", seedISO, "-volid", "cidata", "-joliet", "-rock",
+		filepath.Join(seedDir, "user-data"), filepath.Join(seedDir, "meta-data"))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return VMHandle{}, classifyError(err, stderr.String())
+	}
+
+	runCmd := exec.CommandContext(ctx, "qemu-system-x86_64",
+		"-name", spec.Name,
+		"-smp", fmt.Sprintf("%d", spec.CPUs),
+		"-m", spec.Memory,
+		"-drive", fmt.Sprintf("file=%s,if=virtio,format=qcow2", spec.ImageOCI),
+		"-drive", fmt.Sprintf("file=%s,if=virtio,format=raw", seedISO),
+		"-daemonize",
+	)
+	runCmd.Stderr = &stderr
+	if err := runCmd.Run(); err != nil {
+		return VMHandle{}, classifyError(err, stderr.String())
+	}
+
+	logger.Info("Provisioning VM via qemu backend: %s", spec.Name)
+	return VMHandle{Name: spec.Name, UID: spec.UID}, nil
+}
+
+func (qemuProvisioner) Delete(ctx context.Context, name string) error {
+	cmd := exec.CommandContext(ctx, "pkill", "-f", fmt.Sprintf("-name %s", name))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return classifyError(err, stderr.String())
+	}
+	return nil
+}
+
+func (qemuProvisioner) Get(ctx context.Context, name string) (VMStatus, error) {
+	vms, err := (qemuProvisioner{}).List(ctx)
+	if err != nil {
+		return VMStatus{}, err
+	}
+	for _, vm := range vms {
+		if vm.Name == name {
+			return vm, nil
+		}
+	}
+	return VMStatus{}, fmt.Errorf("%w: %q", ErrVMNotFound, name)
+}
+
+func (qemuProvisioner) List(ctx context.Context) ([]VMStatus, error) {
+	cmd := exec.CommandContext(ctx, "pgrep", "-af", "qemu-system-x86_64")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	// pgrep exits 1 when nothing matches; that just means no VMs are running.
+	_ = cmd.Run()
+
+	var vms []VMStatus
+	for _, line := range bytes.Split(stdout.Bytes(), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		name, ok := qemuNameFromCmdline(string(line))
+		if !ok {
+			continue
+		}
+		vms = append(vms, VMStatus{Name: name, Running: true})
+	}
+	return vms, nil
+}
+
+// qemuNameFromCmdline extracts the VM name from a "pgrep -af" line, which
+// has the form "<pid> <full cmdline>". The name is whatever argument
+// follows the process's own "-name" flag, not the raw line itself.
+func qemuNameFromCmdline(line string) (string, bool) {
+	fields := strings.Fields(line)
+	for i, field := range fields {
+		if field == "-name" && i+1 < len(fields) {
+			return fields[i+1], true
+		}
+	}
+	return "", false
+}
+
+func (qemuProvisioner) Exec(ctx context.Context, name string, cmd []string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("qemu backend does not support Exec without a guest agent")
+}
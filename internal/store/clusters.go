@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ClusterStatus is the lifecycle state of a cluster's master node.
+type ClusterStatus string
+
+const (
+	ClusterStatusProvisioning ClusterStatus = "provisioning"
+	ClusterStatusReady        ClusterStatus = "ready"
+	ClusterStatusFailed       ClusterStatus = "failed"
+)
+
+// ErrClusterNotFound is returned when a cluster lookup matches no record.
+var ErrClusterNotFound = errors.New("store: cluster not found")
+
+// Cluster is a persisted record of one master-plus-workers k3s cluster.
+// Worker VMs belonging to it are found via VM.ClusterName rather than
+// stored inline here.
+type Cluster struct {
+	Name           string
+	MasterNodeName string
+	MasterIP       string
+	JoinToken      string
+	Kubeconfig     string
+	Status         ClusterStatus
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ClusterRepository persists cluster records so master IP, join token, and
+// kubeconfig no longer need to be manually correlated across VM records.
+type ClusterRepository interface {
+	CreateCluster(ctx context.Context, cluster Cluster) error
+	GetCluster(ctx context.Context, name string) (*Cluster, error)
+	ListClusters(ctx context.Context) ([]Cluster, error)
+	DeleteCluster(ctx context.Context, name string) error
+}
+
+func (r *SQLiteRepository) migrateClusters() error {
+	const schema = `
+CREATE TABLE IF NOT EXISTS clusters (
+	name             TEXT PRIMARY KEY,
+	master_node_name TEXT NOT NULL,
+	master_ip        TEXT NOT NULL DEFAULT '',
+	join_token       TEXT NOT NULL DEFAULT '',
+	kubeconfig       TEXT NOT NULL DEFAULT '',
+	status           TEXT NOT NULL DEFAULT 'provisioning',
+	created_at       TIMESTAMP NOT NULL,
+	updated_at       TIMESTAMP NOT NULL
+);
+`
+	_, err := r.db.Exec(schema)
+	if err != nil {
+		return fmt.Errorf("failed to run cluster migrations: %w", err)
+	}
+	return nil
+}
+
+// CreateCluster inserts a new cluster record.
+func (r *SQLiteRepository) CreateCluster(ctx context.Context, cluster Cluster) error {
+	if cluster.Status == "" {
+		cluster.Status = ClusterStatusProvisioning
+	}
+	now := time.Now().UTC()
+	if cluster.CreatedAt.IsZero() {
+		cluster.CreatedAt = now
+	}
+	cluster.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO clusters (name, master_node_name, master_ip, join_token, kubeconfig, status, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		cluster.Name, cluster.MasterNodeName, cluster.MasterIP, cluster.JoinToken, cluster.Kubeconfig,
+		cluster.Status, cluster.CreatedAt, cluster.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster record: %w", err)
+	}
+	return nil
+}
+
+// GetCluster returns the cluster record for name, or ErrClusterNotFound.
+func (r *SQLiteRepository) GetCluster(ctx context.Context, name string) (*Cluster, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT name, master_node_name, master_ip, join_token, kubeconfig, status, created_at, updated_at
+FROM clusters WHERE name = ?`, name)
+	return scanCluster(row)
+}
+
+// ListClusters returns every cluster record, most recently created first.
+func (r *SQLiteRepository) ListClusters(ctx context.Context) ([]Cluster, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT name, master_node_name, master_ip, join_token, kubeconfig, status, created_at, updated_at
+FROM clusters ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	defer rows.Close()
+
+	var clusters []Cluster
+	for rows.Next() {
+		cluster, err := scanCluster(rows)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, *cluster)
+	}
+	return clusters, rows.Err()
+}
+
+// DeleteCluster removes the cluster record for name. Callers are
+// responsible for tearing down its VMs first.
+func (r *SQLiteRepository) DeleteCluster(ctx context.Context, name string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM clusters WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete cluster record: %w", err)
+	}
+	return nil
+}
+
+func scanCluster(row scanner) (*Cluster, error) {
+	var cluster Cluster
+	var status string
+	err := row.Scan(&cluster.Name, &cluster.MasterNodeName, &cluster.MasterIP, &cluster.JoinToken,
+		&cluster.Kubeconfig, &status, &cluster.CreatedAt, &cluster.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrClusterNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan cluster record: %w", err)
+	}
+	cluster.Status = ClusterStatus(status)
+	return &cluster, nil
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"ignite-api/internal/logger"
+	"io"
+	"os"
+	"time"
+)
+
+// importLegacyCSV migrates records from the pre-SQLite provisioned_vms.csv
+// into the vms table on first startup. It is a no-op if the file is absent
+// or the table already has data, so it is safe to call on every Open.
+func (r *SQLiteRepository) importLegacyCSV(path string) error {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM vms`).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check existing vm count: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open legacy CSV file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read legacy CSV header: %w", err)
+	}
+	_ = header // NodeName, NodeUID, MasterIP, NodeType, Token
+
+	ctx := context.Background()
+	imported := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read legacy CSV record: %w", err)
+		}
+		if len(record) < 5 {
+			continue
+		}
+
+		now := time.Now().UTC()
+		if err := r.Create(ctx, VM{
+			NodeName:  record[0],
+			NodeUID:   record[1],
+			MasterIP:  record[2],
+			NodeType:  record[3],
+			Token:     record[4],
+			Status:    StatusReady,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}); err != nil {
+			return fmt.Errorf("failed to import legacy CSV record for %q: %w", record[0], err)
+		}
+		imported++
+	}
+
+	if imported > 0 {
+		logger.Info("imported %d VM record(s) from legacy %s", imported, path)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JobPhase is the current stage of an asynchronous provisioning job.
+type JobPhase string
+
+const (
+	JobPhaseQueued          JobPhase = "queued"
+	JobPhaseWritingManifest JobPhase = "writing-manifest"
+	JobPhaseIgniteRun       JobPhase = "ignite-run"
+	JobPhaseFetchingIP      JobPhase = "fetching-ip"
+	JobPhaseStoring         JobPhase = "storing"
+	JobPhaseComplete        JobPhase = "complete"
+	JobPhaseFailed          JobPhase = "failed"
+)
+
+// ErrJobNotFound is returned when a job lookup matches no record.
+var ErrJobNotFound = errors.New("store: job not found")
+
+// Job is a persisted record of one asynchronous provisioning attempt.
+type Job struct {
+	ID          string
+	NodeType    string
+	RequestJSON string
+	Phase       JobPhase
+	Error       string
+	Stdout      string
+	Stderr      string
+	ResultJSON  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// JobRepository persists provisioning job state so progress survives a
+// server restart.
+type JobRepository interface {
+	CreateJob(ctx context.Context, job Job) error
+	GetJob(ctx context.Context, id string) (*Job, error)
+	ListJobs(ctx context.Context) ([]Job, error)
+	UpdateJobPhase(ctx context.Context, id string, phase JobPhase, errMsg string) error
+	SetJobResult(ctx context.Context, id string, resultJSON string) error
+	AppendJobOutput(ctx context.Context, id string, stdout, stderr string) error
+}
+
+func (r *SQLiteRepository) migrateJobs() error {
+	const schema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id           TEXT PRIMARY KEY,
+	node_type    TEXT NOT NULL DEFAULT '',
+	request_json TEXT NOT NULL DEFAULT '',
+	phase        TEXT NOT NULL DEFAULT 'queued',
+	error        TEXT NOT NULL DEFAULT '',
+	stdout       TEXT NOT NULL DEFAULT '',
+	stderr       TEXT NOT NULL DEFAULT '',
+	result_json  TEXT NOT NULL DEFAULT '',
+	created_at   TIMESTAMP NOT NULL,
+	updated_at   TIMESTAMP NOT NULL
+);
+`
+	if _, err := r.db.Exec(schema); err != nil {
+		return fmt.Errorf("failed to run job migrations: %w", err)
+	}
+	return nil
+}
+
+// CreateJob inserts a new job row in the queued phase.
+func (r *SQLiteRepository) CreateJob(ctx context.Context, job Job) error {
+	if job.Phase == "" {
+		job.Phase = JobPhaseQueued
+	}
+	now := time.Now().UTC()
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = now
+	}
+	job.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO jobs (id, node_type, request_json, phase, error, stdout, stderr, result_json, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.NodeType, job.RequestJSON, job.Phase, job.Error, job.Stdout, job.Stderr, job.ResultJSON,
+		job.CreatedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create job record: %w", err)
+	}
+	return nil
+}
+
+// GetJob returns the job record for id, or ErrJobNotFound.
+func (r *SQLiteRepository) GetJob(ctx context.Context, id string) (*Job, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT id, node_type, request_json, phase, error, stdout, stderr, result_json, created_at, updated_at
+FROM jobs WHERE id = ?`, id)
+	return scanJob(row)
+}
+
+// ListJobs returns every job record, most recently created first.
+func (r *SQLiteRepository) ListJobs(ctx context.Context) ([]Job, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, node_type, request_json, phase, error, stdout, stderr, result_json, created_at, updated_at
+FROM jobs ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+// UpdateJobPhase transitions a job to phase, recording errMsg if non-empty.
+func (r *SQLiteRepository) UpdateJobPhase(ctx context.Context, id string, phase JobPhase, errMsg string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE jobs SET phase = ?, error = ?, updated_at = ? WHERE id = ?`,
+		phase, errMsg, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update job phase: %w", err)
+	}
+	return checkRowsAffected(res, ErrJobNotFound)
+}
+
+// SetJobResult stores the final JSON-encoded result for a completed job.
+func (r *SQLiteRepository) SetJobResult(ctx context.Context, id string, resultJSON string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE jobs SET result_json = ?, updated_at = ? WHERE id = ?`,
+		resultJSON, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set job result: %w", err)
+	}
+	return checkRowsAffected(res, ErrJobNotFound)
+}
+
+// AppendJobOutput appends captured ignite stdout/stderr to the job record.
+func (r *SQLiteRepository) AppendJobOutput(ctx context.Context, id string, stdout, stderr string) error {
+	res, err := r.db.ExecContext(ctx, `
+UPDATE jobs SET stdout = stdout || ?, stderr = stderr || ?, updated_at = ? WHERE id = ?`,
+		stdout, stderr, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to append job output: %w", err)
+	}
+	return checkRowsAffected(res, ErrJobNotFound)
+}
+
+func checkRowsAffected(res sql.Result, notFoundErr error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFoundErr
+	}
+	return nil
+}
+
+func scanJob(row scanner) (*Job, error) {
+	var job Job
+	var phase string
+	err := row.Scan(&job.ID, &job.NodeType, &job.RequestJSON, &phase, &job.Error, &job.Stdout, &job.Stderr,
+		&job.ResultJSON, &job.CreatedAt, &job.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan job record: %w", err)
+	}
+	job.Phase = JobPhase(phase)
+	return &job, nil
+}
@@ -0,0 +1,229 @@
+// Package store persists provisioned VM records. It replaces the original
+// flat-file provisioned_vms.csv with an embedded SQLite database so lookups
+// are indexed and concurrent provisioning requests don't race on file I/O.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Status is the lifecycle state of a provisioned VM.
+type Status string
+
+const (
+	StatusProvisioning Status = "provisioning"
+	StatusReady        Status = "ready"
+	StatusFailed       Status = "failed"
+	StatusDeleted      Status = "deleted"
+)
+
+// ErrNotFound is returned when a lookup matches no VM record.
+var ErrNotFound = errors.New("store: vm not found")
+
+// VM is a persisted record of a provisioned node.
+type VM struct {
+	NodeName    string
+	NodeUID     string
+	MasterIP    string
+	NodeType    string
+	Token       string
+	ImageOCI    string
+	Backend     string
+	Status      Status
+	Kubeconfig  string
+	ClusterName string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// VMRepository is the persistence port used by the API handlers. It is
+// implemented by SQLiteRepository; tests and alternate backends can provide
+// their own implementation.
+type VMRepository interface {
+	Create(ctx context.Context, vm VM) error
+	Get(ctx context.Context, nodeName string) (*VM, error)
+	List(ctx context.Context) ([]VM, error)
+	Delete(ctx context.Context, nodeName string) error
+	FindByMasterIPAndToken(ctx context.Context, masterIP, token string) (*VM, error)
+	UpdateStatus(ctx context.Context, nodeName string, status Status) error
+}
+
+// SQLiteRepository is a VMRepository backed by an embedded SQLite database.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// Open opens (creating if necessary) the SQLite database at path, runs
+// migrations, and imports any pre-existing provisioned_vms.csv on first run.
+func Open(path string) (*SQLiteRepository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	repo := &SQLiteRepository{db: db}
+	if err := repo.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := repo.migrateJobs(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := repo.migrateTokens(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := repo.migrateClusters(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := repo.importLegacyCSV("provisioned_vms.csv"); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Close releases the underlying database handle.
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *SQLiteRepository) migrate() error {
+	const schema = `
+CREATE TABLE IF NOT EXISTS vms (
+	node_name   TEXT PRIMARY KEY,
+	node_uid    TEXT NOT NULL,
+	master_ip   TEXT NOT NULL DEFAULT '',
+	node_type   TEXT NOT NULL DEFAULT '',
+	token       TEXT NOT NULL DEFAULT '',
+	image_oci   TEXT NOT NULL DEFAULT '',
+	backend     TEXT NOT NULL DEFAULT '',
+	status      TEXT NOT NULL DEFAULT 'provisioning',
+	kubeconfig  TEXT NOT NULL DEFAULT '',
+	cluster_name TEXT NOT NULL DEFAULT '',
+	created_at  TIMESTAMP NOT NULL,
+	updated_at  TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_vms_master_ip_token ON vms(master_ip, token);
+`
+	_, err := r.db.Exec(schema)
+	if err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return nil
+}
+
+// Create inserts a new VM record. CreatedAt defaults to now if zero, and
+// updated_at is stored equal to CreatedAt; vm.UpdatedAt is not used.
+func (r *SQLiteRepository) Create(ctx context.Context, vm VM) error {
+	if vm.Status == "" {
+		vm.Status = StatusProvisioning
+	}
+	now := vm.CreatedAt
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO vms (node_name, node_uid, master_ip, node_type, token, image_oci, backend, status, kubeconfig, cluster_name, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		vm.NodeName, vm.NodeUID, vm.MasterIP, vm.NodeType, vm.Token, vm.ImageOCI, vm.Backend, vm.Status, vm.Kubeconfig, vm.ClusterName, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create vm record: %w", err)
+	}
+	return nil
+}
+
+// Get returns the VM record for nodeName, or ErrNotFound.
+func (r *SQLiteRepository) Get(ctx context.Context, nodeName string) (*VM, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT node_name, node_uid, master_ip, node_type, token, image_oci, backend, status, kubeconfig, cluster_name, created_at, updated_at
+FROM vms WHERE node_name = ?`, nodeName)
+	return scanVM(row)
+}
+
+// List returns every VM record, most recently created first.
+func (r *SQLiteRepository) List(ctx context.Context) ([]VM, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT node_name, node_uid, master_ip, node_type, token, image_oci, backend, status, kubeconfig, cluster_name, created_at, updated_at
+FROM vms ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vms: %w", err)
+	}
+	defer rows.Close()
+
+	var vms []VM
+	for rows.Next() {
+		vm, err := scanVM(rows)
+		if err != nil {
+			return nil, err
+		}
+		vms = append(vms, *vm)
+	}
+	return vms, rows.Err()
+}
+
+// Delete removes the VM record for nodeName.
+func (r *SQLiteRepository) Delete(ctx context.Context, nodeName string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM vms WHERE node_name = ?`, nodeName)
+	if err != nil {
+		return fmt.Errorf("failed to delete vm record: %w", err)
+	}
+	return nil
+}
+
+// FindByMasterIPAndToken looks up the VM record matching masterIP and token,
+// replacing the old CSV scan used to validate worker join requests.
+func (r *SQLiteRepository) FindByMasterIPAndToken(ctx context.Context, masterIP, token string) (*VM, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT node_name, node_uid, master_ip, node_type, token, image_oci, backend, status, kubeconfig, cluster_name, created_at, updated_at
+FROM vms WHERE master_ip = ? AND token = ?`, masterIP, token)
+	return scanVM(row)
+}
+
+// UpdateStatus sets status and bumps updated_at for nodeName.
+func (r *SQLiteRepository) UpdateStatus(ctx context.Context, nodeName string, status Status) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE vms SET status = ?, updated_at = ? WHERE node_name = ?`,
+		status, time.Now().UTC(), nodeName)
+	if err != nil {
+		return fmt.Errorf("failed to update vm status: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update vm status: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVM(row scanner) (*VM, error) {
+	var vm VM
+	var status string
+	err := row.Scan(&vm.NodeName, &vm.NodeUID, &vm.MasterIP, &vm.NodeType, &vm.Token, &vm.ImageOCI,
+		&vm.Backend, &status, &vm.Kubeconfig, &vm.ClusterName, &vm.CreatedAt, &vm.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan vm record: %w", err)
+	}
+	vm.Status = Status(status)
+	return &vm, nil
+}
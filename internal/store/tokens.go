@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTokenNotFound is returned when a token lookup matches no active record.
+var ErrTokenNotFound = errors.New("store: token not found")
+
+// APIToken is a persisted API credential. Only HashedSecret is ever stored;
+// the raw bearer token cannot be recovered from the database.
+type APIToken struct {
+	ID             string
+	Name           string
+	HashedSecret   string
+	Role           string
+	ScopedMasterIP string
+	Revoked        bool
+	CreatedAt      time.Time
+}
+
+// TokenRepository persists API tokens for internal/auth's bearer
+// authenticator.
+type TokenRepository interface {
+	CreateToken(ctx context.Context, token APIToken) error
+	GetTokenByHash(ctx context.Context, hashedSecret string) (*APIToken, error)
+	ListTokens(ctx context.Context) ([]APIToken, error)
+	RevokeToken(ctx context.Context, id string) error
+}
+
+func (r *SQLiteRepository) migrateTokens() error {
+	const schema = `
+CREATE TABLE IF NOT EXISTS api_tokens (
+	id               TEXT PRIMARY KEY,
+	name             TEXT NOT NULL DEFAULT '',
+	hashed_secret    TEXT NOT NULL UNIQUE,
+	role             TEXT NOT NULL DEFAULT 'viewer',
+	scoped_master_ip TEXT NOT NULL DEFAULT '',
+	revoked          INTEGER NOT NULL DEFAULT 0,
+	created_at       TIMESTAMP NOT NULL
+);
+`
+	_, err := r.db.Exec(schema)
+	if err != nil {
+		return fmt.Errorf("failed to run token migrations: %w", err)
+	}
+	return nil
+}
+
+// CreateToken inserts a new, active API token record.
+func (r *SQLiteRepository) CreateToken(ctx context.Context, token APIToken) error {
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now().UTC()
+	}
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO api_tokens (id, name, hashed_secret, role, scoped_master_ip, revoked, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		token.ID, token.Name, token.HashedSecret, token.Role, token.ScopedMasterIP, token.Revoked, token.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create api token: %w", err)
+	}
+	return nil
+}
+
+// GetTokenByHash looks up a non-revoked token by its hashed secret.
+func (r *SQLiteRepository) GetTokenByHash(ctx context.Context, hashedSecret string) (*APIToken, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT id, name, hashed_secret, role, scoped_master_ip, revoked, created_at
+FROM api_tokens WHERE hashed_secret = ? AND revoked = 0`, hashedSecret)
+	return scanToken(row)
+}
+
+// ListTokens returns every token record, including revoked ones, most
+// recently created first.
+func (r *SQLiteRepository) ListTokens(ctx context.Context) ([]APIToken, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, name, hashed_secret, role, scoped_master_ip, revoked, created_at
+FROM api_tokens ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []APIToken
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeToken marks a token as revoked so GetTokenByHash stops returning it.
+func (r *SQLiteRepository) RevokeToken(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE api_tokens SET revoked = 1 WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+	return checkRowsAffected(res, ErrTokenNotFound)
+}
+
+func scanToken(row scanner) (*APIToken, error) {
+	var token APIToken
+	var revoked bool
+	err := row.Scan(&token.ID, &token.Name, &token.HashedSecret, &token.Role, &token.ScopedMasterIP, &revoked, &token.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan api token: %w", err)
+	}
+	token.Revoked = revoked
+	return &token, nil
+}
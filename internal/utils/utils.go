@@ -0,0 +1,28 @@
+// Package utils provides small filesystem helpers shared across VM backends.
+package utils
+
+import (
+	"fmt"
+	"os"
+)
+
+// WriteTempFile writes a temporary file with the given data and pattern.
+func WriteTempFile(data []byte, pattern string) (string, error) {
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("error creating temp file: %w", err)
+	}
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return "", fmt.Errorf("error writing to temp file: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", fmt.Errorf("error closing temp file: %w", err)
+	}
+
+	return tmpFile.Name(), nil
+}